@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// newBackend returns the NVML implementation selected with --nvidia.backend
+func newBackend(name string, fixture string) (nvml.Interface, error) {
+	switch name {
+	case "nvml":
+		return nvml.New(), nil
+	case "fake":
+		if fixture == "" {
+			return nil, fmt.Errorf("the fake backend requires --nvidia.fake-fixture")
+		}
+		return newFakeNvml(fixture)
+	default:
+		return nil, fmt.Errorf("unknown backend %q, expected one of: nvml, fake", name)
+	}
+}
+
+// returnCodes maps the names of NVML return codes to their values
+var returnCodes = map[string]nvml.Return{
+	"SUCCESS":                         nvml.SUCCESS,
+	"ERROR_UNINITIALIZED":             nvml.ERROR_UNINITIALIZED,
+	"ERROR_INVALID_ARGUMENT":          nvml.ERROR_INVALID_ARGUMENT,
+	"ERROR_NOT_SUPPORTED":             nvml.ERROR_NOT_SUPPORTED,
+	"ERROR_NO_PERMISSION":             nvml.ERROR_NO_PERMISSION,
+	"ERROR_ALREADY_INITIALIZED":       nvml.ERROR_ALREADY_INITIALIZED,
+	"ERROR_NOT_FOUND":                 nvml.ERROR_NOT_FOUND,
+	"ERROR_INSUFFICIENT_SIZE":         nvml.ERROR_INSUFFICIENT_SIZE,
+	"ERROR_INSUFFICIENT_POWER":        nvml.ERROR_INSUFFICIENT_POWER,
+	"ERROR_DRIVER_NOT_LOADED":         nvml.ERROR_DRIVER_NOT_LOADED,
+	"ERROR_TIMEOUT":                   nvml.ERROR_TIMEOUT,
+	"ERROR_IRQ_ISSUE":                 nvml.ERROR_IRQ_ISSUE,
+	"ERROR_LIBRARY_NOT_FOUND":         nvml.ERROR_LIBRARY_NOT_FOUND,
+	"ERROR_FUNCTION_NOT_FOUND":        nvml.ERROR_FUNCTION_NOT_FOUND,
+	"ERROR_CORRUPTED_INFOROM":         nvml.ERROR_CORRUPTED_INFOROM,
+	"ERROR_GPU_IS_LOST":               nvml.ERROR_GPU_IS_LOST,
+	"ERROR_RESET_REQUIRED":            nvml.ERROR_RESET_REQUIRED,
+	"ERROR_OPERATING_SYSTEM":          nvml.ERROR_OPERATING_SYSTEM,
+	"ERROR_LIB_RM_VERSION_MISMATCH":   nvml.ERROR_LIB_RM_VERSION_MISMATCH,
+	"ERROR_IN_USE":                    nvml.ERROR_IN_USE,
+	"ERROR_MEMORY":                    nvml.ERROR_MEMORY,
+	"ERROR_NO_DATA":                   nvml.ERROR_NO_DATA,
+	"ERROR_VGPU_ECC_NOT_SUPPORTED":    nvml.ERROR_VGPU_ECC_NOT_SUPPORTED,
+	"ERROR_INSUFFICIENT_RESOURCES":    nvml.ERROR_INSUFFICIENT_RESOURCES,
+	"ERROR_FREQ_NOT_SUPPORTED":        nvml.ERROR_FREQ_NOT_SUPPORTED,
+	"ERROR_ARGUMENT_VERSION_MISMATCH": nvml.ERROR_ARGUMENT_VERSION_MISMATCH,
+	"ERROR_DEPRECATED":                nvml.ERROR_DEPRECATED,
+	"ERROR_UNKNOWN":                   nvml.ERROR_UNKNOWN,
+}
+
+// returnName returns the short lowercase name of an NVML return code,
+// e.g. gpu_is_lost for ERROR_GPU_IS_LOST
+func returnName(ret nvml.Return) string {
+	for name, r := range returnCodes {
+		if r == ret {
+			return strings.ToLower(strings.TrimPrefix(name, "ERROR_"))
+		}
+	}
+	return fmt.Sprintf("unknown_%d", int32(ret))
+}
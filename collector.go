@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector gathers one group of per-device metrics
+type Collector interface {
+	// Update queries device and stores the results in d, failed NVML calls
+	// should be recorded with d.check rather than returned
+	Update(lib nvml.Interface, device nvml.Device, d *Device) error
+	// Describe sends the descriptors of every metric the collector exports
+	Describe(ch chan<- *prometheus.Desc)
+	// Collect exports the metrics stored in d by Update
+	Collect(d *Device, ch chan<- prometheus.Metric)
+}
+
+// fieldReader is implemented by collectors which read NVML fields. The
+// fields of every collector are read with a single GetFieldValues call before
+// Update, which gets the values with d.fieldValue.
+type fieldReader interface {
+	// Fields returns the fields to read, only FieldId and ScopeId are used
+	Fields() []nvml.FieldValue
+}
+
+// deviceStateKeeper is implemented by collectors which keep state between
+// collections by device UUID
+type deviceStateKeeper interface {
+	// Retain is called before each collection with the UUIDs of the devices
+	// present, the state kept for other devices must be released
+	Retain(uuids map[string]bool)
+}
+
+// collectorConfig holds the settings passed to collector factories
+type collectorConfig struct {
+	// DeviceLabels are the device attributes every per-device metric is labelled with
+	DeviceLabels     []string
+	StripProcessArgs bool
+	StripProcessPath bool
+	// EncoderSessions makes the encoder collector export every encoder and
+	// FBC session
+	EncoderSessions bool
+	// Fields are the NVML fields read by the fields collector
+	Fields []fieldConfig
+	// MetricNames, when set, collects the name of every metric described
+	// with newDeviceDesc
+	MetricNames *[]string
+}
+
+// collectorResult is the outcome of running one collector on a device
+type collectorResult struct {
+	Duration time.Duration
+	Err      error
+	// Metrics lists the metrics the collector checked support for
+	Metrics []string
+}
+
+var (
+	factories      = make(map[string]func(cfg collectorConfig) Collector)
+	collectorState = make(map[string]*bool)
+)
+
+// collectorFlag sets the state of a collector, it backs both the
+// --collector.<name> and --no-collector.<name> flags
+type collectorFlag struct {
+	state *bool
+	value bool
+}
+
+func (f *collectorFlag) IsBoolFlag() bool { return true }
+
+func (f *collectorFlag) String() string {
+	if f.state == nil {
+		return ""
+	}
+	return strconv.FormatBool(*f.state == f.value)
+}
+
+func (f *collectorFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*f.state = v == f.value
+	return nil
+}
+
+// registerCollector makes a collector available and adds the flags to
+// enable and disable it, it must be called from init
+func registerCollector(name string, isDefaultEnabled bool, factory func(cfg collectorConfig) Collector) {
+	state := isDefaultEnabled
+	var help string
+	if isDefaultEnabled {
+		help = fmt.Sprintf("Enable the %s collector (default: enabled)", name)
+	} else {
+		help = fmt.Sprintf("Enable the %s collector (default: disabled)", name)
+	}
+	flag.Var(&collectorFlag{state: &state, value: true}, "collector."+name, help)
+	flag.Var(&collectorFlag{state: &state, value: false}, "no-collector."+name, fmt.Sprintf("Disable the %s collector", name))
+	collectorState[name] = &state
+	factories[name] = factory
+}
+
+// newCollectors creates every enabled collector
+func newCollectors(cfg collectorConfig) map[string]Collector {
+	collectors := make(map[string]Collector)
+	for name, enabled := range collectorState {
+		if *enabled {
+			collectors[name] = factories[name](cfg)
+		}
+	}
+	return collectors
+}
+
+// filterCollectors returns the subset of collectors requested with collect[]
+func filterCollectors(collectors map[string]Collector, names []string) (map[string]Collector, error) {
+	filtered := make(map[string]Collector)
+	for _, name := range names {
+		if _, ok := collectorState[name]; !ok {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+		c, ok := collectors[name]
+		if !ok {
+			return nil, fmt.Errorf("collector %q is disabled", name)
+		}
+		filtered[name] = c
+	}
+	return filtered, nil
+}
+
+// collectorNames returns the sorted names of collectors
+func collectorNames(collectors map[string]Collector) []string {
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// newDeviceDesc returns the descriptor of a per-device metric, labelled
+// with the device labels followed by extra
+func newDeviceDesc(cfg collectorConfig, name string, help string, extra ...string) *prometheus.Desc {
+	fqName := prometheus.BuildFQName(namespace, "", name)
+	if cfg.MetricNames != nil {
+		*cfg.MetricNames = append(*cfg.MetricNames, fqName)
+	}
+	return prometheus.NewDesc(fqName, help, withLabels(cfg.DeviceLabels, extra...), nil)
+}
+
+// collectorMetricNames returns the names of the metrics of every collector,
+// enabled or not, leaving out configured fields
+func collectorMetricNames(cfg collectorConfig) []string {
+	var names []string
+	cfg.MetricNames = &names
+	cfg.Fields = nil
+	for _, factory := range factories {
+		factory(cfg)
+	}
+	return names
+}
+
+// deviceGauge sends a gauge if the NVML call behind metric succeeded on d
+func deviceGauge(ch chan<- prometheus.Metric, d *Device, desc *prometheus.Desc, metric string, value float64, labels ...string) {
+	if d.Supported[metric] {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	}
+}
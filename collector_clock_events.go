@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// clockEventReasons maps the bits of the clocks event reason mask to the
+// values of the reason label
+var clockEventReasons = []struct {
+	mask uint64
+	name string
+}{
+	{nvml.ClocksEventReasonGpuIdle, "idle"},
+	{nvml.ClocksEventReasonApplicationsClocksSetting, "applications_clocks"},
+	{nvml.ClocksEventReasonSwPowerCap, "sw_power_cap"},
+	{nvml.ClocksThrottleReasonHwSlowdown, "hw_slowdown"},
+	{nvml.ClocksEventReasonSyncBoost, "sync_boost"},
+	{nvml.ClocksEventReasonSwThermalSlowdown, "sw_thermal"},
+	{nvml.ClocksThrottleReasonHwThermalSlowdown, "hw_thermal"},
+	{nvml.ClocksThrottleReasonHwPowerBrakeSlowdown, "hw_power_brake"},
+}
+
+type clockEventsCollector struct {
+	labels            []string
+	clockEventReasons *prometheus.Desc
+}
+
+func init() {
+	registerCollector("clock_events", true, newClockEventsCollector)
+}
+
+func newClockEventsCollector(cfg collectorConfig) Collector {
+	return &clockEventsCollector{
+		labels:            cfg.DeviceLabels,
+		clockEventReasons: newDeviceDesc(cfg, "clock_event_reason_active", "Whether clocks are currently held down for the given reason", "reason"),
+	}
+}
+
+func (c *clockEventsCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	supported, ret := device.GetSupportedClocksEventReasons()
+	if !d.check("GetSupportedClocksEventReasons", ret, "clock_event_reason_active") {
+		return nil
+	}
+	current, ret := device.GetCurrentClocksEventReasons()
+	if d.check("GetCurrentClocksEventReasons", ret, "clock_event_reason_active") {
+		d.ClockEventReasons = current
+		d.SupportedClockEventReasons = supported
+	}
+	return nil
+}
+
+func (c *clockEventsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clockEventReasons
+}
+
+func (c *clockEventsCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	if !d.Supported["clock_event_reason_active"] {
+		return
+	}
+	for _, reason := range clockEventReasons {
+		if d.SupportedClockEventReasons&reason.mask == 0 {
+			continue
+		}
+		active := d.ClockEventReasons&reason.mask != 0
+		ch <- prometheus.MustNewConstMetric(c.clockEventReasons, prometheus.GaugeValue, boolFloat(active),
+			d.labelValues(c.labels, reason.name)...)
+	}
+}
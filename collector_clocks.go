@@ -0,0 +1,162 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// clockTypes maps NVML clock domains to the values of the clock label
+var clockTypes = []struct {
+	clockType nvml.ClockType
+	name      string
+}{
+	{nvml.CLOCK_GRAPHICS, "graphics"},
+	{nvml.CLOCK_SM, "sm"},
+	{nvml.CLOCK_MEM, "memory"},
+	{nvml.CLOCK_VIDEO, "video"},
+}
+
+// utilizationDomains maps the domains of GetDynamicPstatesInfo to label values
+var utilizationDomains = []struct {
+	domain nvml.GpuUtilizationDomainId
+	name   string
+}{
+	{nvml.GPU_UTILIZATION_DOMAIN_GPU, "gpu"},
+	{nvml.GPU_UTILIZATION_DOMAIN_FB, "fb"},
+	{nvml.GPU_UTILIZATION_DOMAIN_VID, "vid"},
+	{nvml.GPU_UTILIZATION_DOMAIN_BUS, "bus"},
+}
+
+// ClockValue is a clock speed in MHz, Kind says which of the envelope
+// metrics it belongs to
+type ClockValue struct {
+	Kind  string
+	Clock string
+	MHz   float64
+}
+
+type clocksCollector struct {
+	labels               []string
+	clockCurrentGraphics *prometheus.Desc
+	clockCurrentMemory   *prometheus.Desc
+	clockCurrentSM       *prometheus.Desc
+	clockCurrentVideo    *prometheus.Desc
+	performanceState     *prometheus.Desc
+	pstateUtilization    *prometheus.Desc
+	// envelope holds the descriptors of ClockValue kinds
+	envelope map[string]*prometheus.Desc
+}
+
+func init() {
+	registerCollector("clocks", true, newClocksCollector)
+}
+
+func newClocksCollector(cfg collectorConfig) Collector {
+	return &clocksCollector{
+		labels:               cfg.DeviceLabels,
+		clockCurrentGraphics: newDeviceDesc(cfg, "clock_current_graphics", "Current GPU graphics clock speed as reported by the device"),
+		clockCurrentMemory:   newDeviceDesc(cfg, "clock_current_memory", "Current GPU memory clock speed as reported by the device"),
+		clockCurrentSM:       newDeviceDesc(cfg, "clock_current_sm", "Current SM clock speed as reported by the device"),
+		clockCurrentVideo:    newDeviceDesc(cfg, "clock_current_video", "Current video encoder/decoder clock speed as reported by the device"),
+		performanceState:     newDeviceDesc(cfg, "performance_state", "Current performance state, from 0 for maximum performance to 15 for minimum"),
+		pstateUtilization:    newDeviceDesc(cfg, "dynamic_pstate_utilization", "Utilization of each domain driving performance state changes", "domain"),
+		envelope: map[string]*prometheus.Desc{
+			"max":                  newDeviceDesc(cfg, "clock_max", "Maximum clock speed in MHz", "clock"),
+			"max_boost":            newDeviceDesc(cfg, "clock_max_boost", "Maximum customer boost clock speed in MHz", "clock"),
+			"applications":         newDeviceDesc(cfg, "clock_applications", "Application clock speed in MHz", "clock"),
+			"default_applications": newDeviceDesc(cfg, "clock_default_applications", "Default application clock speed in MHz", "clock"),
+			"pstate_min":           newDeviceDesc(cfg, "clock_pstate_min", "Minimum clock speed in MHz of the current performance state", "clock"),
+			"pstate_max":           newDeviceDesc(cfg, "clock_pstate_max", "Maximum clock speed in MHz of the current performance state", "clock"),
+		},
+	}
+}
+
+func (c *clocksCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	clockCurrentGraphics, ret := device.GetClock(nvml.CLOCK_GRAPHICS, nvml.CLOCK_ID_CURRENT)
+	if d.check("GetClock", ret, "clock_current_graphics") {
+		d.ClockCurrentGraphics = float64(clockCurrentGraphics)
+	}
+	clockCurrentMemory, ret := device.GetClock(nvml.CLOCK_MEM, nvml.CLOCK_ID_CURRENT)
+	if d.check("GetClock", ret, "clock_current_memory") {
+		d.ClockCurrentMemory = float64(clockCurrentMemory)
+	}
+	clockCurrentSM, ret := device.GetClock(nvml.CLOCK_SM, nvml.CLOCK_ID_CURRENT)
+	if d.check("GetClock", ret, "clock_current_sm") {
+		d.ClockCurrentSM = float64(clockCurrentSM)
+	}
+	clockCurrentVideo, ret := device.GetClock(nvml.CLOCK_VIDEO, nvml.CLOCK_ID_CURRENT)
+	if d.check("GetClock", ret, "clock_current_video") {
+		d.ClockCurrentVideo = float64(clockCurrentVideo)
+	}
+
+	pstate, ret := device.GetPerformanceState()
+	if ret == nvml.SUCCESS && pstate == nvml.PSTATE_UNKNOWN {
+		ret = nvml.ERROR_NOT_SUPPORTED
+	}
+	if d.check("GetPerformanceState", ret, "performance_state") {
+		d.PerformanceState = float64(pstate)
+	}
+	info, ret := device.GetDynamicPstatesInfo()
+	if d.check("GetDynamicPstatesInfo", ret, "dynamic_pstate_utilization") {
+		d.PstateUtilization = make(map[string]float64)
+		for _, domain := range utilizationDomains {
+			if u := info.Utilization[domain.domain]; u.BIsPresent != 0 {
+				d.PstateUtilization[domain.name] = float64(u.Percentage)
+			}
+		}
+	}
+
+	// Which clocks have limits differs between devices, don't count the ones
+	// that don't as errors
+	record := func(api string, kind string, clock string, mhz uint32, ret nvml.Return) bool {
+		if ret == nvml.ERROR_NOT_SUPPORTED || !d.check(api, ret) {
+			return false
+		}
+		d.Clocks = append(d.Clocks, ClockValue{Kind: kind, Clock: clock, MHz: float64(mhz)})
+		return true
+	}
+	for _, t := range clockTypes {
+		mhz, ret := device.GetMaxClockInfo(t.clockType)
+		record("GetMaxClockInfo", "max", t.name, mhz, ret)
+		mhz, ret = device.GetMaxCustomerBoostClock(t.clockType)
+		record("GetMaxCustomerBoostClock", "max_boost", t.name, mhz, ret)
+		mhz, ret = device.GetApplicationsClock(t.clockType)
+		record("GetApplicationsClock", "applications", t.name, mhz, ret)
+		mhz, ret = device.GetDefaultApplicationsClock(t.clockType)
+		record("GetDefaultApplicationsClock", "default_applications", t.name, mhz, ret)
+		if d.Supported["performance_state"] {
+			minMHz, maxMHz, ret := device.GetMinMaxClockOfPState(t.clockType, pstate)
+			if record("GetMinMaxClockOfPState", "pstate_min", t.name, minMHz, ret) {
+				d.Clocks = append(d.Clocks, ClockValue{Kind: "pstate_max", Clock: t.name, MHz: float64(maxMHz)})
+			}
+		}
+	}
+	return nil
+}
+
+func (c *clocksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clockCurrentGraphics
+	ch <- c.clockCurrentMemory
+	ch <- c.clockCurrentSM
+	ch <- c.clockCurrentVideo
+	ch <- c.performanceState
+	ch <- c.pstateUtilization
+	for _, desc := range c.envelope {
+		ch <- desc
+	}
+}
+
+func (c *clocksCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.clockCurrentGraphics, "clock_current_graphics", d.ClockCurrentGraphics, labels...)
+	deviceGauge(ch, d, c.clockCurrentMemory, "clock_current_memory", d.ClockCurrentMemory, labels...)
+	deviceGauge(ch, d, c.clockCurrentSM, "clock_current_sm", d.ClockCurrentSM, labels...)
+	deviceGauge(ch, d, c.clockCurrentVideo, "clock_current_video", d.ClockCurrentVideo, labels...)
+	deviceGauge(ch, d, c.performanceState, "performance_state", d.PerformanceState, labels...)
+	for domain, utilization := range d.PstateUtilization {
+		ch <- prometheus.MustNewConstMetric(c.pstateUtilization, prometheus.GaugeValue, utilization, d.labelValues(c.labels, domain)...)
+	}
+	for _, v := range d.Clocks {
+		ch <- prometheus.MustNewConstMetric(c.envelope[v.Kind], prometheus.GaugeValue, v.MHz, d.labelValues(c.labels, v.Clock)...)
+	}
+}
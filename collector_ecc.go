@@ -0,0 +1,130 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// eccErrorTypes and eccCounterTypes map NVML enums to label values
+var (
+	eccErrorTypes = []struct {
+		typ  nvml.MemoryErrorType
+		name string
+	}{
+		{nvml.MEMORY_ERROR_TYPE_CORRECTED, "corrected"},
+		{nvml.MEMORY_ERROR_TYPE_UNCORRECTED, "uncorrected"},
+	}
+	eccCounterTypes = []struct {
+		typ  nvml.EccCounterType
+		name string
+	}{
+		{nvml.VOLATILE_ECC, "volatile"},
+		{nvml.AGGREGATE_ECC, "aggregate"},
+	}
+	// eccLocations are read with GetMemoryErrorCounter, SRAM is read with
+	// GetSramEccErrorStatus instead
+	eccLocations = []struct {
+		location nvml.MemoryLocation
+		name     string
+	}{
+		{nvml.MEMORY_LOCATION_L1_CACHE, "l1_cache"},
+		{nvml.MEMORY_LOCATION_L2_CACHE, "l2_cache"},
+		{nvml.MEMORY_LOCATION_DEVICE_MEMORY, "device_memory"},
+		{nvml.MEMORY_LOCATION_REGISTER_FILE, "register_file"},
+		{nvml.MEMORY_LOCATION_TEXTURE_MEMORY, "texture_memory"},
+		{nvml.MEMORY_LOCATION_TEXTURE_SHM, "texture_shm"},
+		{nvml.MEMORY_LOCATION_CBU, "cbu"},
+	}
+)
+
+// eccErrorCount is the value of one ECC error counter
+type eccErrorCount struct {
+	Type     string
+	Counter  string
+	Location string
+	Count    uint64
+}
+
+type eccCollector struct {
+	labels                []string
+	eccModeCurrent        *prometheus.Desc
+	eccModePending        *prometheus.Desc
+	eccErrors             *prometheus.Desc
+	sramThresholdExceeded *prometheus.Desc
+}
+
+func init() {
+	registerCollector("ecc", true, newEccCollector)
+}
+
+func newEccCollector(cfg collectorConfig) Collector {
+	return &eccCollector{
+		labels:                cfg.DeviceLabels,
+		eccModeCurrent:        newDeviceDesc(cfg, "ecc_mode_current", "Whether ECC is currently enabled"),
+		eccModePending:        newDeviceDesc(cfg, "ecc_mode_pending", "Whether ECC will be enabled after the next reboot"),
+		eccErrors:             newDeviceDesc(cfg, "ecc_errors_total", "ECC errors by memory location, location=\"all\" is the total over every location", "type", "counter", "location"),
+		sramThresholdExceeded: newDeviceDesc(cfg, "ecc_sram_threshold_exceeded", "Whether uncorrected SRAM errors exceeded the threshold for a GPU reset"),
+	}
+}
+
+func (c *eccCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	current, pending, ret := device.GetEccMode()
+	// Consumer cards don't have ECC, there is nothing else to read
+	if !d.check("GetEccMode", ret, "ecc_mode_current", "ecc_mode_pending") {
+		return nil
+	}
+	d.EccModeCurrent = boolFloat(current == nvml.FEATURE_ENABLED)
+	d.EccModePending = boolFloat(pending == nvml.FEATURE_ENABLED)
+
+	for _, errorType := range eccErrorTypes {
+		for _, counterType := range eccCounterTypes {
+			total, ret := device.GetTotalEccErrors(errorType.typ, counterType.typ)
+			if !d.check("GetTotalEccErrors", ret, "ecc_errors_total") {
+				continue
+			}
+			d.EccErrors = append(d.EccErrors, eccErrorCount{errorType.name, counterType.name, "all", total})
+			for _, location := range eccLocations {
+				count, ret := device.GetMemoryErrorCounter(errorType.typ, counterType.typ, location.location)
+				if ret == nvml.ERROR_NOT_SUPPORTED {
+					continue
+				}
+				if d.check("GetMemoryErrorCounter", ret) {
+					d.EccErrors = append(d.EccErrors, eccErrorCount{errorType.name, counterType.name, location.name, count})
+				}
+			}
+		}
+	}
+
+	sram, ret := device.GetSramEccErrorStatus()
+	if ret == nvml.ERROR_NOT_SUPPORTED {
+		return nil
+	}
+	if d.check("GetSramEccErrorStatus", ret, "ecc_sram_threshold_exceeded") {
+		d.EccErrors = append(d.EccErrors,
+			eccErrorCount{"corrected", "volatile", "sram", sram.VolatileCor},
+			eccErrorCount{"uncorrected", "volatile", "sram", sram.VolatileUncParity + sram.VolatileUncSecDed},
+			eccErrorCount{"corrected", "aggregate", "sram", sram.AggregateCor},
+			eccErrorCount{"uncorrected", "aggregate", "sram", sram.AggregateUncParity + sram.AggregateUncSecDed},
+		)
+		d.SramThresholdExceeded = boolFloat(sram.BThresholdExceeded != 0)
+	}
+	return nil
+}
+
+func (c *eccCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.eccModeCurrent
+	ch <- c.eccModePending
+	ch <- c.eccErrors
+	ch <- c.sramThresholdExceeded
+}
+
+func (c *eccCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.eccModeCurrent, "ecc_mode_current", d.EccModeCurrent, labels...)
+	deviceGauge(ch, d, c.eccModePending, "ecc_mode_pending", d.EccModePending, labels...)
+	deviceGauge(ch, d, c.sramThresholdExceeded, "ecc_sram_threshold_exceeded", d.SramThresholdExceeded, labels...)
+	for _, e := range d.EccErrors {
+		ch <- prometheus.MustNewConstMetric(c.eccErrors, prometheus.CounterValue, float64(e.Count),
+			d.labelValues(c.labels, e.Type, e.Counter, e.Location)...)
+	}
+}
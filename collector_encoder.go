@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// encoderCodecs maps encoder types to codec label values
+var encoderCodecs = map[nvml.EncoderType]string{
+	nvml.ENCODER_QUERY_H264: "h264",
+	nvml.ENCODER_QUERY_HEVC: "hevc",
+	nvml.ENCODER_QUERY_AV1:  "av1",
+}
+
+// fbcSessionTypes maps frame buffer capture session types to label values
+var fbcSessionTypes = map[nvml.FBCSessionType]string{
+	nvml.FBC_SESSION_TYPE_UNKNOWN: "unknown",
+	nvml.FBC_SESSION_TYPE_TOSYS:   "tosys",
+	nvml.FBC_SESSION_TYPE_CUDA:    "cuda",
+	nvml.FBC_SESSION_TYPE_VID:     "vid",
+	nvml.FBC_SESSION_TYPE_HWENC:   "hwenc",
+}
+
+// EncoderSession is an active encoder or frame buffer capture session, Type
+// is the codec of an encoder session and the capture type of an FBC session
+type EncoderSession struct {
+	ID             string
+	PID            string
+	Type           string
+	Resolution     string
+	AverageFPS     float64
+	AverageLatency float64
+}
+
+type encoderCollector struct {
+	labels                []string
+	sessions              bool
+	utilizationDecoder    *prometheus.Desc
+	utilizationEncoder    *prometheus.Desc
+	encoderSessions       *prometheus.Desc
+	encoderAverageFPS     *prometheus.Desc
+	encoderAverageLatency *prometheus.Desc
+	encoderCapacity       *prometheus.Desc
+	fbcSessions           *prometheus.Desc
+	fbcAverageFPS         *prometheus.Desc
+	fbcAverageLatency     *prometheus.Desc
+	encoderSessionFPS     *prometheus.Desc
+	encoderSessionLatency *prometheus.Desc
+	fbcSessionFPS         *prometheus.Desc
+	fbcSessionLatency     *prometheus.Desc
+}
+
+func init() {
+	registerCollector("encoder", true, newEncoderCollector)
+}
+
+func newEncoderCollector(cfg collectorConfig) Collector {
+	return &encoderCollector{
+		labels:                cfg.DeviceLabels,
+		sessions:              cfg.EncoderSessions,
+		utilizationDecoder:    newDeviceDesc(cfg, "utilization_decoder", "Decoder utilization as reported by the device"),
+		utilizationEncoder:    newDeviceDesc(cfg, "utilization_encoder", "Encoder utilization as reported by the device"),
+		encoderSessions:       newDeviceDesc(cfg, "encoder_sessions", "Active encoder sessions"),
+		encoderAverageFPS:     newDeviceDesc(cfg, "encoder_average_fps", "Average frame rate of the encoder sessions"),
+		encoderAverageLatency: newDeviceDesc(cfg, "encoder_average_latency_seconds", "Average latency of the encoder sessions"),
+		encoderCapacity:       newDeviceDesc(cfg, "encoder_capacity_percent", "Remaining encoder capacity by codec", "codec"),
+		fbcSessions:           newDeviceDesc(cfg, "fbc_sessions", "Active frame buffer capture sessions"),
+		fbcAverageFPS:         newDeviceDesc(cfg, "fbc_average_fps", "Average frame rate of the frame buffer capture sessions"),
+		fbcAverageLatency:     newDeviceDesc(cfg, "fbc_average_latency_seconds", "Average latency of the frame buffer capture sessions"),
+		encoderSessionFPS:     newDeviceDesc(cfg, "encoder_session_average_fps", "Average frame rate of the encoder session", "session_id", "pid", "codec", "resolution"),
+		encoderSessionLatency: newDeviceDesc(cfg, "encoder_session_average_latency_seconds", "Average latency of the encoder session", "session_id", "pid", "codec", "resolution"),
+		fbcSessionFPS:         newDeviceDesc(cfg, "fbc_session_average_fps", "Average frame rate of the frame buffer capture session", "session_id", "pid", "type", "resolution"),
+		fbcSessionLatency:     newDeviceDesc(cfg, "fbc_session_average_latency_seconds", "Average latency of the frame buffer capture session", "session_id", "pid", "type", "resolution"),
+	}
+}
+
+func (c *encoderCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	decUtil, _, ret := device.GetDecoderUtilization()
+	if d.check("GetDecoderUtilization", ret, "utilization_decoder") {
+		d.UtilizationDecoder = float64(decUtil)
+	}
+	encUtil, _, ret := device.GetEncoderUtilization()
+	if d.check("GetEncoderUtilization", ret, "utilization_encoder") {
+		d.UtilizationEncoder = float64(encUtil)
+	}
+	sessions, fps, latency, ret := device.GetEncoderStats()
+	if d.check("GetEncoderStats", ret, "encoder_sessions", "encoder_average_fps", "encoder_average_latency_seconds") {
+		d.EncoderSessions = float64(sessions)
+		d.EncoderAverageFPS = float64(fps)
+		d.EncoderAverageLatency = float64(latency) / 1e6
+	}
+	for codec, name := range encoderCodecs {
+		capacity, ret := device.GetEncoderCapacity(codec)
+		// Codecs the encoder doesn't have aren't errors
+		if ret == nvml.ERROR_NOT_SUPPORTED || !d.check("GetEncoderCapacity", ret) {
+			continue
+		}
+		if d.EncoderCapacity == nil {
+			d.EncoderCapacity = make(map[string]float64)
+		}
+		d.EncoderCapacity[name] = float64(capacity)
+	}
+	fbc, ret := device.GetFBCStats()
+	if d.check("GetFBCStats", ret, "fbc_sessions", "fbc_average_fps", "fbc_average_latency_seconds") {
+		d.FBCSessions = float64(fbc.SessionsCount)
+		d.FBCAverageFPS = float64(fbc.AverageFPS)
+		d.FBCAverageLatency = float64(fbc.AverageLatency) / 1e6
+	}
+	if !c.sessions {
+		return nil
+	}
+	encoderSessions, ret := device.GetEncoderSessions()
+	if d.check("GetEncoderSessions", ret) {
+		for _, s := range encoderSessions {
+			d.EncoderSessionList = append(d.EncoderSessionList, &EncoderSession{
+				ID:             strconv.FormatUint(uint64(s.SessionId), 10),
+				PID:            strconv.FormatUint(uint64(s.Pid), 10),
+				Type:           encoderCodecs[nvml.EncoderType(s.CodecType)],
+				Resolution:     fmt.Sprintf("%dx%d", s.HResolution, s.VResolution),
+				AverageFPS:     float64(s.AverageFps),
+				AverageLatency: float64(s.AverageLatency) / 1e6,
+			})
+		}
+	}
+	fbcSessions, ret := device.GetFBCSessions()
+	if d.check("GetFBCSessions", ret) {
+		for _, s := range fbcSessions {
+			d.FBCSessionList = append(d.FBCSessionList, &EncoderSession{
+				ID:             strconv.FormatUint(uint64(s.SessionId), 10),
+				PID:            strconv.FormatUint(uint64(s.Pid), 10),
+				Type:           fbcSessionTypes[nvml.FBCSessionType(s.SessionType)],
+				Resolution:     fmt.Sprintf("%dx%d", s.HResolution, s.VResolution),
+				AverageFPS:     float64(s.AverageFPS),
+				AverageLatency: float64(s.AverageLatency) / 1e6,
+			})
+		}
+	}
+	return nil
+}
+
+func (c *encoderCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.utilizationDecoder
+	ch <- c.utilizationEncoder
+	ch <- c.encoderSessions
+	ch <- c.encoderAverageFPS
+	ch <- c.encoderAverageLatency
+	ch <- c.encoderCapacity
+	ch <- c.fbcSessions
+	ch <- c.fbcAverageFPS
+	ch <- c.fbcAverageLatency
+	if c.sessions {
+		ch <- c.encoderSessionFPS
+		ch <- c.encoderSessionLatency
+		ch <- c.fbcSessionFPS
+		ch <- c.fbcSessionLatency
+	}
+}
+
+func (c *encoderCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.utilizationDecoder, "utilization_decoder", d.UtilizationDecoder, labels...)
+	deviceGauge(ch, d, c.utilizationEncoder, "utilization_encoder", d.UtilizationEncoder, labels...)
+	deviceGauge(ch, d, c.encoderSessions, "encoder_sessions", d.EncoderSessions, labels...)
+	deviceGauge(ch, d, c.encoderAverageFPS, "encoder_average_fps", d.EncoderAverageFPS, labels...)
+	deviceGauge(ch, d, c.encoderAverageLatency, "encoder_average_latency_seconds", d.EncoderAverageLatency, labels...)
+	for codec, capacity := range d.EncoderCapacity {
+		ch <- prometheus.MustNewConstMetric(c.encoderCapacity, prometheus.GaugeValue, capacity, d.labelValues(c.labels, codec)...)
+	}
+	deviceGauge(ch, d, c.fbcSessions, "fbc_sessions", d.FBCSessions, labels...)
+	deviceGauge(ch, d, c.fbcAverageFPS, "fbc_average_fps", d.FBCAverageFPS, labels...)
+	deviceGauge(ch, d, c.fbcAverageLatency, "fbc_average_latency_seconds", d.FBCAverageLatency, labels...)
+	for _, s := range d.EncoderSessionList {
+		sessionLabels := d.labelValues(c.labels, s.ID, s.PID, s.Type, s.Resolution)
+		ch <- prometheus.MustNewConstMetric(c.encoderSessionFPS, prometheus.GaugeValue, s.AverageFPS, sessionLabels...)
+		ch <- prometheus.MustNewConstMetric(c.encoderSessionLatency, prometheus.GaugeValue, s.AverageLatency, sessionLabels...)
+	}
+	for _, s := range d.FBCSessionList {
+		sessionLabels := d.labelValues(c.labels, s.ID, s.PID, s.Type, s.Resolution)
+		ch <- prometheus.MustNewConstMetric(c.fbcSessionFPS, prometheus.GaugeValue, s.AverageFPS, sessionLabels...)
+		ch <- prometheus.MustNewConstMetric(c.fbcSessionLatency, prometheus.GaugeValue, s.AverageLatency, sessionLabels...)
+	}
+}
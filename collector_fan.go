@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fanControlPolicies maps fan control policies to label values
+var fanControlPolicies = map[nvml.FanControlPolicy]string{
+	nvml.FAN_POLICY_TEMPERATURE_CONTINOUS_SW: "auto",
+	nvml.FAN_POLICY_MANUAL:                   "manual",
+}
+
+// Fan holds the state of a single fan, fields which couldn't be read are left
+// empty or negative
+type Fan struct {
+	Fan         string
+	Speed       float64
+	TargetSpeed float64
+	Policy      string
+}
+
+type fanCollector struct {
+	labels         []string
+	fanSpeed       *prometheus.Desc
+	fanSpeedPerFan *prometheus.Desc
+	fanTarget      *prometheus.Desc
+	fanMin         *prometheus.Desc
+	fanMax         *prometheus.Desc
+	fanPolicy      *prometheus.Desc
+}
+
+func init() {
+	registerCollector("fan", true, newFanCollector)
+}
+
+func newFanCollector(cfg collectorConfig) Collector {
+	return &fanCollector{
+		labels:         cfg.DeviceLabels,
+		fanSpeed:       newDeviceDesc(cfg, "fanspeed", "Fan speed as reported by the device"),
+		fanSpeedPerFan: newDeviceDesc(cfg, "fan_speed_percent", "Speed of each fan in percent of its maximum", "fan"),
+		fanTarget:      newDeviceDesc(cfg, "fan_target_speed_percent", "Speed each fan is driven towards in percent of its maximum", "fan"),
+		fanMin:         newDeviceDesc(cfg, "fan_speed_min_percent", "Minimum speed the fans can be set to"),
+		fanMax:         newDeviceDesc(cfg, "fan_speed_max_percent", "Maximum speed the fans can be set to"),
+		fanPolicy:      newDeviceDesc(cfg, "fan_control_policy", "Control policy of each fan", "fan", "policy"),
+	}
+}
+
+func (c *fanCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	fanSpeed, ret := device.GetFanSpeed()
+	if d.check("GetFanSpeed", ret, "fanspeed") {
+		d.FanSpeed = float64(fanSpeed)
+	}
+
+	numFans, ret := device.GetNumFans()
+	if !d.check("GetNumFans", ret, "fan_speed_percent") || numFans == 0 {
+		return nil
+	}
+	minSpeed, maxSpeed, ret := device.GetMinMaxFanSpeed()
+	if d.check("GetMinMaxFanSpeed", ret, "fan_speed_min_percent", "fan_speed_max_percent") {
+		d.FanSpeedMin = float64(minSpeed)
+		d.FanSpeedMax = float64(maxSpeed)
+	}
+	for fan := range numFans {
+		f := &Fan{Fan: strconv.Itoa(fan), Speed: -1, TargetSpeed: -1}
+		speed, ret := device.GetFanSpeed_v2(fan)
+		if d.check("GetFanSpeed_v2", ret) {
+			f.Speed = float64(speed)
+		}
+		target, ret := device.GetTargetFanSpeed(fan)
+		if d.check("GetTargetFanSpeed", ret) {
+			f.TargetSpeed = float64(target)
+		}
+		policy, ret := device.GetFanControlPolicy_v2(fan)
+		if d.check("GetFanControlPolicy_v2", ret) {
+			f.Policy = fanControlPolicies[policy]
+		}
+		d.Fans = append(d.Fans, f)
+	}
+	return nil
+}
+
+func (c *fanCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.fanSpeed
+	ch <- c.fanSpeedPerFan
+	ch <- c.fanTarget
+	ch <- c.fanMin
+	ch <- c.fanMax
+	ch <- c.fanPolicy
+}
+
+func (c *fanCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.fanSpeed, "fanspeed", d.FanSpeed, labels...)
+	deviceGauge(ch, d, c.fanMin, "fan_speed_min_percent", d.FanSpeedMin, labels...)
+	deviceGauge(ch, d, c.fanMax, "fan_speed_max_percent", d.FanSpeedMax, labels...)
+	for _, f := range d.Fans {
+		if f.Speed >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.fanSpeedPerFan, prometheus.GaugeValue, f.Speed, d.labelValues(c.labels, f.Fan)...)
+		}
+		if f.TargetSpeed >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.fanTarget, prometheus.GaugeValue, f.TargetSpeed, d.labelValues(c.labels, f.Fan)...)
+		}
+		if f.Policy != "" {
+			ch <- prometheus.MustNewConstMetric(c.fanPolicy, prometheus.GaugeValue, 1, d.labelValues(c.labels, f.Fan, f.Policy)...)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// FieldResult is a value read for a configured field
+type FieldResult struct {
+	// Field is the index of the field in the configuration
+	Field int
+	Scope string
+	Value float64
+}
+
+type fieldsCollector struct {
+	labels []string
+	fields []fieldConfig
+	descs  []*prometheus.Desc
+}
+
+func init() {
+	registerCollector("fields", true, newFieldsCollector)
+}
+
+func newFieldsCollector(cfg collectorConfig) Collector {
+	c := &fieldsCollector{labels: cfg.DeviceLabels, fields: cfg.Fields}
+	for _, f := range cfg.Fields {
+		if len(f.Scopes) > 0 {
+			c.descs = append(c.descs, newDeviceDesc(cfg, f.Name, f.Help, "scope"))
+		} else {
+			c.descs = append(c.descs, newDeviceDesc(cfg, f.Name, f.Help))
+		}
+	}
+	return c
+}
+
+func (c *fieldsCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	for i, f := range c.fields {
+		scopes := f.Scopes
+		if len(scopes) == 0 {
+			scopes = []uint32{0}
+		}
+		supported := false
+		for _, scopeID := range scopes {
+			v, ok := d.fieldValue(f.ID, scopeID)
+			if !ok {
+				continue
+			}
+			value, ok := fieldValue(v)
+			if !ok {
+				continue
+			}
+			var scope string
+			if len(f.Scopes) > 0 {
+				scope = strconv.FormatUint(uint64(scopeID), 10)
+			}
+			d.Fields = append(d.Fields, FieldResult{Field: i, Scope: scope, Value: value * f.Scale})
+			supported = true
+		}
+		d.setSupported(supported, f.Name)
+	}
+	return nil
+}
+
+func (c *fieldsCollector) Fields() []nvml.FieldValue {
+	var values []nvml.FieldValue
+	for _, f := range c.fields {
+		if len(f.Scopes) == 0 {
+			values = append(values, nvml.FieldValue{FieldId: f.ID})
+		}
+		for _, scope := range f.Scopes {
+			values = append(values, nvml.FieldValue{FieldId: f.ID, ScopeId: scope})
+		}
+	}
+	return values
+}
+
+func (c *fieldsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *fieldsCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	for _, r := range d.Fields {
+		f := c.fields[r.Field]
+		valueType := prometheus.GaugeValue
+		if f.Type == "counter" {
+			valueType = prometheus.CounterValue
+		}
+		var labels []string
+		if len(f.Scopes) > 0 {
+			labels = d.labelValues(c.labels, r.Scope)
+		} else {
+			labels = d.labelValues(c.labels)
+		}
+		ch <- prometheus.MustNewConstMetric(c.descs[r.Field], valueType, r.Value, labels...)
+	}
+}
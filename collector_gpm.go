@@ -0,0 +1,276 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gpmMetrics are the GPM metrics exported, scale turns the NVML value into
+// the unit of the metric
+var gpmMetrics = []struct {
+	id    nvml.GpmMetricId
+	name  string
+	help  string
+	scale float64
+}{
+	{nvml.GPM_METRIC_SM_UTIL, "sm_activity_percent", "Percentage of time the SMs were busy", 1},
+	{nvml.GPM_METRIC_SM_OCCUPANCY, "sm_occupancy_percent", "Percentage of warps resident on the SMs relative to the maximum", 1},
+	{nvml.GPM_METRIC_ANY_TENSOR_UTIL, "tensor_activity_percent", "Percentage of time any tensor pipe was active", 1},
+	{nvml.GPM_METRIC_FP16_UTIL, "fp16_activity_percent", "Percentage of time the FP16 pipe was active", 1},
+	{nvml.GPM_METRIC_FP32_UTIL, "fp32_activity_percent", "Percentage of time the FP32 pipe was active", 1},
+	{nvml.GPM_METRIC_FP64_UTIL, "fp64_activity_percent", "Percentage of time the FP64 pipe was active", 1},
+	{nvml.GPM_METRIC_DRAM_BW_UTIL, "dram_bandwidth_utilization_percent", "Percentage of the peak DRAM bandwidth used", 1},
+	{nvml.GPM_METRIC_PCIE_TX_PER_SEC, "pcie_tx_bytes_per_second", "PCIe traffic sent by the GPU", 1 << 20},
+	{nvml.GPM_METRIC_PCIE_RX_PER_SEC, "pcie_rx_bytes_per_second", "PCIe traffic received by the GPU", 1 << 20},
+	{nvml.GPM_METRIC_NVLINK_TOTAL_TX_PER_SEC, "nvlink_tx_bytes_per_second", "NVLink traffic sent by the GPU over all links", 1 << 20},
+	{nvml.GPM_METRIC_NVLINK_TOTAL_RX_PER_SEC, "nvlink_rx_bytes_per_second", "NVLink traffic received by the GPU over all links", 1 << 20},
+}
+
+// GpmMig holds the GPM metrics of a MIG GPU instance, indexed like
+// gpmMetrics, values which couldn't be read are negative
+type GpmMig struct {
+	GpuInstance string
+	Values      []float64
+}
+
+// gpmSamples are the two samples GPM metrics are computed from, previous is
+// valid once a sample has been taken
+type gpmSamples struct {
+	previous nvml.GpmSample
+	current  nvml.GpmSample
+	valid    bool
+	// generation is the session generation the samples were taken in
+	generation uint64
+	// mig holds the samples of each MIG GPU instance by ID
+	mig map[int]*gpmSamples
+}
+
+type gpmCollector struct {
+	labels   []string
+	names    []string
+	descs    []*prometheus.Desc
+	migDescs []*prometheus.Desc
+
+	// samples are kept between collections by device UUID. A device is
+	// never updated by two collections at once, so mu only guards the map,
+	// entries are removed by Retain once their device is gone.
+	mu      sync.Mutex
+	samples map[string]*gpmSamples
+}
+
+func init() {
+	registerCollector("gpm", true, newGpmCollector)
+}
+
+func newGpmCollector(cfg collectorConfig) Collector {
+	c := &gpmCollector{labels: cfg.DeviceLabels, samples: make(map[string]*gpmSamples)}
+	for _, m := range gpmMetrics {
+		c.names = append(c.names, "gpm_"+m.name)
+		c.descs = append(c.descs, newDeviceDesc(cfg, "gpm_"+m.name, m.help+", averaged since the last collection"))
+		c.migDescs = append(c.migDescs, newDeviceDesc(cfg, "gpm_mig_"+m.name, m.help+" on the MIG GPU instance, averaged since the last collection", "gpu_instance"))
+	}
+	return c
+}
+
+// deviceSamples returns the samples kept for the device with the given UUID,
+// samples taken before NVML was initialized again are released
+func (c *gpmCollector) deviceSamples(uuid string, generation uint64) *gpmSamples {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.samples[uuid]
+	if ok && s.generation != generation {
+		s.release()
+		ok = false
+	}
+	if !ok {
+		s = &gpmSamples{generation: generation, mig: make(map[int]*gpmSamples)}
+		c.samples[uuid] = s
+	}
+	return s
+}
+
+func (c *gpmCollector) Retain(uuids map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uuid, s := range c.samples {
+		if !uuids[uuid] {
+			s.release()
+			delete(c.samples, uuid)
+		}
+	}
+}
+
+func (c *gpmCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	support, ret := device.GpmQueryDeviceSupport()
+	if ret == nvml.SUCCESS && support.IsSupportedDevice == 0 {
+		ret = nvml.ERROR_NOT_SUPPORTED
+	}
+	if !d.check("GpmQueryDeviceSupport", ret, c.names...) {
+		return nil
+	}
+	s := c.deviceSamples(d.UUID, d.generation)
+
+	// The first collection only takes a sample, metrics are exported from
+	// the second one on
+	metrics := s.next(lib, d, "GpmSampleGet", device.GpmSampleGet, c.names...)
+	if metrics != nil {
+		d.Gpm = make([]float64, len(gpmMetrics))
+		for i, m := range gpmMetrics {
+			if d.check("GpmMetricsGet", nvml.Return(metrics.Metrics[i].NvmlReturn), c.names[i]) {
+				d.Gpm[i] = metrics.Metrics[i].Value * m.scale
+			}
+		}
+	}
+
+	gpuInstances := make(map[int]bool)
+	for _, gi := range migGpuInstances(device, d) {
+		gpuInstances[gi] = true
+		migSamples, ok := s.mig[gi]
+		if !ok {
+			migSamples = &gpmSamples{}
+			s.mig[gi] = migSamples
+		}
+		get := func(sample nvml.GpmSample) nvml.Return {
+			return device.GpmMigSampleGet(gi, sample)
+		}
+		metrics := migSamples.next(lib, d, "GpmMigSampleGet", get)
+		if metrics == nil {
+			continue
+		}
+		m := &GpmMig{GpuInstance: strconv.Itoa(gi), Values: make([]float64, len(gpmMetrics))}
+		for i, metric := range gpmMetrics {
+			m.Values[i] = -1
+			ret := nvml.Return(metrics.Metrics[i].NvmlReturn)
+			// Most GPU instances don't have every metric, e.g. NVLink
+			if ret == nvml.ERROR_NOT_SUPPORTED || !d.check("GpmMetricsGet", ret) {
+				continue
+			}
+			m.Values[i] = metrics.Metrics[i].Value * metric.scale
+		}
+		d.GpmMig = append(d.GpmMig, m)
+	}
+	// Free the samples of GPU instances which were destroyed, a new
+	// instance may reuse the ID
+	for gi, migSamples := range s.mig {
+		if !gpuInstances[gi] {
+			migSamples.free()
+			delete(s.mig, gi)
+		}
+	}
+	return nil
+}
+
+// next takes a new sample with get and returns the metrics since the
+// previous one, or nil if there is no previous sample or a call failed.
+// The failures are recorded on d for api and metrics.
+func (s *gpmSamples) next(lib nvml.Interface, d *Device, api string, get func(nvml.GpmSample) nvml.Return, metrics ...string) *nvml.GpmMetricsGetType {
+	if s.current == nil {
+		for _, sample := range []*nvml.GpmSample{&s.previous, &s.current} {
+			var ret nvml.Return
+			*sample, ret = lib.GpmSampleAlloc()
+			if !d.check("GpmSampleAlloc", ret, metrics...) {
+				s.free()
+				return nil
+			}
+		}
+	}
+	if !d.check(api, get(s.current), metrics...) {
+		// Don't compute metrics over the gap
+		s.valid = false
+		return nil
+	}
+	if !s.valid {
+		s.previous, s.current = s.current, s.previous
+		s.valid = true
+		return nil
+	}
+	result := &nvml.GpmMetricsGetType{
+		NumMetrics: uint32(len(gpmMetrics)),
+		Sample1:    s.previous,
+		Sample2:    s.current,
+	}
+	for i, m := range gpmMetrics {
+		result.Metrics[i].MetricId = uint32(m.id)
+	}
+	ret := lib.GpmMetricsGet(result)
+	s.previous, s.current = s.current, s.previous
+	if !d.check("GpmMetricsGet", ret, metrics...) {
+		return nil
+	}
+	return result
+}
+
+// free releases the samples
+func (s *gpmSamples) free() {
+	for _, sample := range []nvml.GpmSample{s.previous, s.current} {
+		if sample != nil {
+			sample.Free()
+		}
+	}
+	s.previous, s.current, s.valid = nil, nil, false
+}
+
+// release frees the samples and those of every MIG GPU instance
+func (s *gpmSamples) release() {
+	s.free()
+	for gi, migSamples := range s.mig {
+		migSamples.free()
+		delete(s.mig, gi)
+	}
+}
+
+// migGpuInstances returns the IDs of the GPU instances on device, none if
+// MIG is disabled
+func migGpuInstances(device nvml.Device, d *Device) []int {
+	// The mig collector reports why MIG mode can't be read
+	current, _, ret := device.GetMigMode()
+	if ret != nvml.SUCCESS || current != nvml.DEVICE_MIG_ENABLE {
+		return nil
+	}
+	maxDevices, ret := device.GetMaxMigDeviceCount()
+	if !d.check("GetMaxMigDeviceCount", ret) {
+		return nil
+	}
+	var ids []int
+	seen := make(map[int]bool)
+	for index := range maxDevices {
+		migDevice, ret := device.GetMigDeviceHandleByIndex(index)
+		if ret == nvml.ERROR_NOT_FOUND || !d.check("GetMigDeviceHandleByIndex", ret) {
+			continue
+		}
+		gi, ret := migDevice.GetGpuInstanceId()
+		if !d.check("GetGpuInstanceId", ret) || seen[gi] {
+			continue
+		}
+		seen[gi] = true
+		ids = append(ids, gi)
+	}
+	return ids
+}
+
+func (c *gpmCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+	for _, desc := range c.migDescs {
+		ch <- desc
+	}
+}
+
+func (c *gpmCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	for i, value := range d.Gpm {
+		deviceGauge(ch, d, c.descs[i], c.names[i], value, labels...)
+	}
+	for _, m := range d.GpmMig {
+		migLabels := d.labelValues(c.labels, m.GpuInstance)
+		for i, value := range m.Values {
+			if value >= 0 {
+				ch <- prometheus.MustNewConstMetric(c.migDescs[i], prometheus.GaugeValue, value, migLabels...)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type memoryCollector struct {
+	labels      []string
+	memoryTotal *prometheus.Desc
+	memoryUsed  *prometheus.Desc
+}
+
+func init() {
+	registerCollector("memory", true, newMemoryCollector)
+}
+
+func newMemoryCollector(cfg collectorConfig) Collector {
+	return &memoryCollector{
+		labels:      cfg.DeviceLabels,
+		memoryTotal: newDeviceDesc(cfg, "memory_total", "Total memory as reported by the device"),
+		memoryUsed:  newDeviceDesc(cfg, "memory_used", "Used memory as reported by the device"),
+	}
+}
+
+func (c *memoryCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	memoryInfo, ret := device.GetMemoryInfo()
+	if d.check("GetMemoryInfo", ret, "memory_total", "memory_used") {
+		d.MemoryTotal = float64(memoryInfo.Total)
+		d.MemoryUsed = float64(memoryInfo.Used)
+	}
+	return nil
+}
+
+func (c *memoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.memoryTotal
+	ch <- c.memoryUsed
+}
+
+func (c *memoryCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.memoryTotal, "memory_total", d.MemoryTotal, labels...)
+	deviceGauge(ch, d, c.memoryUsed, "memory_used", d.MemoryUsed, labels...)
+}
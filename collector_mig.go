@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// migLabels are the labels of per-MIG-device metrics, after the device labels
+var migLabels = []string{"gpu_instance", "compute_instance", "profile", "mig_uuid"}
+
+// MigDevice holds the state of a MIG device, values which couldn't be read
+// are negative
+type MigDevice struct {
+	UUID              string
+	GpuInstance       string
+	ComputeInstance   string
+	Profile           string
+	MemoryTotal       float64
+	MemoryUsed        float64
+	UtilizationGPU    float64
+	UtilizationMemory float64
+	Processes         []MigProcess
+}
+
+// MigProcess is a process running on a MIG device
+type MigProcess struct {
+	PID        string
+	MemoryUsed float64
+}
+
+type migCollector struct {
+	labels            []string
+	migMode           *prometheus.Desc
+	migModePending    *prometheus.Desc
+	maxDevices        *prometheus.Desc
+	info              *prometheus.Desc
+	memoryTotal       *prometheus.Desc
+	memoryUsed        *prometheus.Desc
+	utilizationGPU    *prometheus.Desc
+	utilizationMemory *prometheus.Desc
+	processMemory     *prometheus.Desc
+}
+
+func init() {
+	registerCollector("mig", true, newMigCollector)
+}
+
+func newMigCollector(cfg collectorConfig) Collector {
+	return &migCollector{
+		labels:            cfg.DeviceLabels,
+		migMode:           newDeviceDesc(cfg, "mig_mode", "1 if MIG mode is enabled"),
+		migModePending:    newDeviceDesc(cfg, "mig_mode_pending", "1 if MIG mode will be enabled after the next GPU reset"),
+		maxDevices:        newDeviceDesc(cfg, "mig_max_devices", "Maximum number of MIG devices the GPU can be split into"),
+		info:              newDeviceDesc(cfg, "mig_info", "A metric with a constant '1' value labeled by MIG device", migLabels...),
+		memoryTotal:       newDeviceDesc(cfg, "mig_memory_total", "Total memory of the MIG device", migLabels...),
+		memoryUsed:        newDeviceDesc(cfg, "mig_memory_used", "Used memory of the MIG device", migLabels...),
+		utilizationGPU:    newDeviceDesc(cfg, "mig_utilization_gpu", "GPU utilization of the MIG device", migLabels...),
+		utilizationMemory: newDeviceDesc(cfg, "mig_utilization_memory", "Memory utilization of the MIG device", migLabels...),
+		processMemory:     newDeviceDesc(cfg, "mig_process_memory_used", "Memory used by each process running on the MIG device", withLabels(migLabels, "pid")...),
+	}
+}
+
+func (c *migCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	current, pending, ret := device.GetMigMode()
+	if !d.check("GetMigMode", ret, "mig_mode", "mig_mode_pending") {
+		return nil
+	}
+	d.MigMode = boolFloat(current == nvml.DEVICE_MIG_ENABLE)
+	d.MigModePending = boolFloat(pending == nvml.DEVICE_MIG_ENABLE)
+	if current != nvml.DEVICE_MIG_ENABLE {
+		return nil
+	}
+	maxDevices, ret := device.GetMaxMigDeviceCount()
+	if !d.check("GetMaxMigDeviceCount", ret, "mig_max_devices") {
+		return nil
+	}
+	d.MigMaxDevices = float64(maxDevices)
+
+	profiles := migProfiles(device, d)
+	for index := range maxDevices {
+		migDevice, ret := device.GetMigDeviceHandleByIndex(index)
+		// Indices without a MIG device return NOT_FOUND
+		if ret == nvml.ERROR_NOT_FOUND || !d.check("GetMigDeviceHandleByIndex", ret) {
+			continue
+		}
+		m := &MigDevice{MemoryTotal: -1, MemoryUsed: -1, UtilizationGPU: -1, UtilizationMemory: -1}
+		uuid, ret := migDevice.GetUUID()
+		if d.check("GetUUID", ret) {
+			m.UUID = uuid
+		}
+		gi, ret := migDevice.GetGpuInstanceId()
+		if d.check("GetGpuInstanceId", ret) {
+			m.GpuInstance = strconv.Itoa(gi)
+		}
+		ci, ret := migDevice.GetComputeInstanceId()
+		if d.check("GetComputeInstanceId", ret) {
+			m.ComputeInstance = strconv.Itoa(ci)
+		}
+		m.Profile = profiles[[2]string{m.GpuInstance, m.ComputeInstance}]
+		memoryInfo, ret := migDevice.GetMemoryInfo()
+		if d.check("GetMemoryInfo", ret) {
+			m.MemoryTotal = float64(memoryInfo.Total)
+			m.MemoryUsed = float64(memoryInfo.Used)
+		}
+		// Most drivers only report utilization of MIG devices through GPM
+		utilization, ret := migDevice.GetUtilizationRates()
+		if d.check("GetUtilizationRates", ret) {
+			m.UtilizationGPU = float64(utilization.Gpu)
+			m.UtilizationMemory = float64(utilization.Memory)
+		}
+		processes, ret := migDevice.GetComputeRunningProcesses()
+		if d.check("GetComputeRunningProcesses", ret) {
+			for _, p := range processes {
+				m.Processes = append(m.Processes, MigProcess{
+					PID:        strconv.FormatUint(uint64(p.Pid), 10),
+					MemoryUsed: float64(p.UsedGpuMemory),
+				})
+			}
+		}
+		d.MigDevices = append(d.MigDevices, m)
+	}
+	return nil
+}
+
+// migProfiles returns the profile names of the compute instances on
+// device, keyed by GPU instance and compute instance ID. The names follow
+// nvidia-smi, e.g. 3g.40gb or 1c.3g.40gb for a compute instance using one of
+// the three slices of its GPU instance.
+func migProfiles(device nvml.Device, d *Device) map[[2]string]string {
+	names := make(map[[2]string]string)
+	for profile := range nvml.GPU_INSTANCE_PROFILE_COUNT {
+		info, ret := device.GetGpuInstanceProfileInfo(profile)
+		// Profiles the device doesn't have aren't errors
+		if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_INVALID_ARGUMENT || !d.check("GetGpuInstanceProfileInfo", ret) {
+			continue
+		}
+		if info.InstanceCount == 0 {
+			continue
+		}
+		giName := fmt.Sprintf("%dg.%dgb", info.SliceCount, (info.MemorySizeMB+1023)/1024)
+		if profile == nvml.GPU_INSTANCE_PROFILE_1_SLICE_REV1 || profile == nvml.GPU_INSTANCE_PROFILE_2_SLICE_REV1 {
+			giName += "+me"
+		}
+		gpuInstances, ret := device.GetGpuInstances(&info)
+		if !d.check("GetGpuInstances", ret) {
+			continue
+		}
+		for _, gpuInstance := range gpuInstances {
+			giInfo, ret := gpuInstance.GetInfo()
+			if !d.check("GetGpuInstanceInfo", ret) {
+				continue
+			}
+			gi := strconv.FormatUint(uint64(giInfo.Id), 10)
+			for ciProfile := range nvml.COMPUTE_INSTANCE_PROFILE_COUNT {
+				ciInfo, ret := gpuInstance.GetComputeInstanceProfileInfo(ciProfile, nvml.COMPUTE_INSTANCE_ENGINE_PROFILE_SHARED)
+				if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_INVALID_ARGUMENT || !d.check("GetComputeInstanceProfileInfo", ret) {
+					continue
+				}
+				if ciInfo.InstanceCount == 0 {
+					continue
+				}
+				computeInstances, ret := gpuInstance.GetComputeInstances(&ciInfo)
+				if !d.check("GetComputeInstances", ret) {
+					continue
+				}
+				name := giName
+				if ciInfo.SliceCount < info.SliceCount {
+					name = fmt.Sprintf("%dc.%s", ciInfo.SliceCount, giName)
+				}
+				for _, computeInstance := range computeInstances {
+					instance, ret := computeInstance.GetInfo()
+					if d.check("GetComputeInstanceInfo", ret) {
+						names[[2]string{gi, strconv.FormatUint(uint64(instance.Id), 10)}] = name
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func (c *migCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.migMode
+	ch <- c.migModePending
+	ch <- c.maxDevices
+	ch <- c.info
+	ch <- c.memoryTotal
+	ch <- c.memoryUsed
+	ch <- c.utilizationGPU
+	ch <- c.utilizationMemory
+	ch <- c.processMemory
+}
+
+func (c *migCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.migMode, "mig_mode", d.MigMode, labels...)
+	deviceGauge(ch, d, c.migModePending, "mig_mode_pending", d.MigModePending, labels...)
+	deviceGauge(ch, d, c.maxDevices, "mig_max_devices", d.MigMaxDevices, labels...)
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		if value >= 0 {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+		}
+	}
+	for _, m := range d.MigDevices {
+		migLabels := d.labelValues(c.labels, m.GpuInstance, m.ComputeInstance, m.Profile, m.UUID)
+		gauge(c.info, 1, migLabels...)
+		gauge(c.memoryTotal, m.MemoryTotal, migLabels...)
+		gauge(c.memoryUsed, m.MemoryUsed, migLabels...)
+		gauge(c.utilizationGPU, m.UtilizationGPU, migLabels...)
+		gauge(c.utilizationMemory, m.UtilizationMemory, migLabels...)
+		for _, p := range m.Processes {
+			gauge(c.processMemory, p.MemoryUsed, withLabels(migLabels, p.PID)...)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// nvLinkErrorCounters maps NVLink error counters to label values
+var nvLinkErrorCounters = []struct {
+	counter nvml.NvLinkErrorCounter
+	name    string
+}{
+	{nvml.NVLINK_ERROR_DL_REPLAY, "replay"},
+	{nvml.NVLINK_ERROR_DL_RECOVERY, "recovery"},
+	{nvml.NVLINK_ERROR_DL_CRC_FLIT, "crc_flit"},
+	{nvml.NVLINK_ERROR_DL_CRC_DATA, "crc_data"},
+}
+
+// nvLinkDeviceTypes maps the device types at the remote end of a link to label values
+var nvLinkDeviceTypes = map[nvml.IntNvLinkDeviceType]string{
+	nvml.NVLINK_DEVICE_TYPE_GPU:     "gpu",
+	nvml.NVLINK_DEVICE_TYPE_IBMNPU:  "ibmnpu",
+	nvml.NVLINK_DEVICE_TYPE_SWITCH:  "switch",
+	nvml.NVLINK_DEVICE_TYPE_UNKNOWN: "unknown",
+}
+
+// NvLink holds the state of a single NVLink of a device
+type NvLink struct {
+	Link           string
+	Up             bool
+	Version        string
+	RemoteType     string
+	RemotePciBusID string
+	// Errors holds the error counters which could be read, by counter name
+	Errors map[string]float64
+	// HasBytes is set if utilization counter 0 counts bytes
+	HasBytes bool
+	RxBytes  float64
+	TxBytes  float64
+}
+
+type nvLinkCollector struct {
+	labels      []string
+	nvLinkUp    *prometheus.Desc
+	nvLinkInfo  *prometheus.Desc
+	nvLinkError *prometheus.Desc
+	nvLinkRx    *prometheus.Desc
+	nvLinkTx    *prometheus.Desc
+}
+
+func init() {
+	registerCollector("nvlink", true, newNvLinkCollector)
+}
+
+func newNvLinkCollector(cfg collectorConfig) Collector {
+	return &nvLinkCollector{
+		labels:      cfg.DeviceLabels,
+		nvLinkUp:    newDeviceDesc(cfg, "nvlink_up", "Whether the NVLink is active", "link"),
+		nvLinkInfo:  newDeviceDesc(cfg, "nvlink_info", "NVLink version and the device at the remote end of the link", "link", "version", "remote_type", "remote_pci_bus_id"),
+		nvLinkError: newDeviceDesc(cfg, "nvlink_errors_total", "NVLink data link errors by counter", "link", "counter"),
+		nvLinkRx:    newDeviceDesc(cfg, "nvlink_received_bytes_total", "Bytes received over the NVLink, counted by utilization counter 0", "link"),
+		nvLinkTx:    newDeviceDesc(cfg, "nvlink_transmitted_bytes_total", "Bytes transmitted over the NVLink, counted by utilization counter 0", "link"),
+	}
+}
+
+func (c *nvLinkCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	for link := range nvml.NVLINK_MAX_LINKS {
+		state, ret := device.GetNvLinkState(link)
+		if link == 0 {
+			// A device without NVLink fails on the first link already
+			if !d.check("GetNvLinkState", ret, "nvlink_up") {
+				return nil
+			}
+		} else if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_INVALID_ARGUMENT {
+			// Links are numbered sparsely on some devices
+			continue
+		} else if !d.check("GetNvLinkState", ret) {
+			continue
+		}
+		l := &NvLink{
+			Link:   strconv.Itoa(link),
+			Up:     state == nvml.FEATURE_ENABLED,
+			Errors: make(map[string]float64),
+		}
+		d.NvLinks = append(d.NvLinks, l)
+
+		if l.Up {
+			version, ret := device.GetNvLinkVersion(link)
+			if d.check("GetNvLinkVersion", ret) {
+				l.Version = strconv.Itoa(int(version))
+			}
+			remoteType, ret := device.GetNvLinkRemoteDeviceType(link)
+			if d.check("GetNvLinkRemoteDeviceType", ret) {
+				l.RemoteType = nvLinkDeviceTypes[remoteType]
+			}
+			remotePciInfo, ret := device.GetNvLinkRemotePciInfo(link)
+			if d.check("GetNvLinkRemotePciInfo", ret) {
+				l.RemotePciBusID = pciBusID(remotePciInfo)
+			}
+		}
+		for _, counter := range nvLinkErrorCounters {
+			count, ret := device.GetNvLinkErrorCounter(link, counter.counter)
+			if d.check("GetNvLinkErrorCounter", ret) {
+				l.Errors[counter.name] = float64(count)
+			}
+		}
+		// The utilization counters count whatever they were configured to,
+		// only export them when counter 0 is set up to count bytes
+		control, ret := device.GetNvLinkUtilizationControl(link, 0)
+		if ret == nvml.ERROR_NOT_SUPPORTED || !d.check("GetNvLinkUtilizationControl", ret) ||
+			nvml.NvLinkUtilizationCountUnits(control.Units) != nvml.NVLINK_COUNTER_UNIT_BYTES {
+			continue
+		}
+		rx, tx, ret := device.GetNvLinkUtilizationCounter(link, 0)
+		if d.check("GetNvLinkUtilizationCounter", ret) {
+			l.HasBytes = true
+			l.RxBytes = float64(rx)
+			l.TxBytes = float64(tx)
+		}
+	}
+	return nil
+}
+
+func (c *nvLinkCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.nvLinkUp
+	ch <- c.nvLinkInfo
+	ch <- c.nvLinkError
+	ch <- c.nvLinkRx
+	ch <- c.nvLinkTx
+}
+
+func (c *nvLinkCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	for _, l := range d.NvLinks {
+		ch <- prometheus.MustNewConstMetric(c.nvLinkUp, prometheus.GaugeValue, boolFloat(l.Up), d.labelValues(c.labels, l.Link)...)
+		if l.Up {
+			ch <- prometheus.MustNewConstMetric(c.nvLinkInfo, prometheus.GaugeValue, 1,
+				d.labelValues(c.labels, l.Link, l.Version, l.RemoteType, l.RemotePciBusID)...)
+		}
+		for counter, count := range l.Errors {
+			ch <- prometheus.MustNewConstMetric(c.nvLinkError, prometheus.CounterValue, count, d.labelValues(c.labels, l.Link, counter)...)
+		}
+		if l.HasBytes {
+			ch <- prometheus.MustNewConstMetric(c.nvLinkRx, prometheus.CounterValue, l.RxBytes, d.labelValues(c.labels, l.Link)...)
+			ch <- prometheus.MustNewConstMetric(c.nvLinkTx, prometheus.CounterValue, l.TxBytes, d.labelValues(c.labels, l.Link)...)
+		}
+	}
+}
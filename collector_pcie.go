@@ -0,0 +1,149 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pcieLoadedUtilization is the GPU utilization above which a link running
+// below its maximum is considered degraded, idle GPUs lower their link
+// generation to save power
+const pcieLoadedUtilization = 30
+
+// pcieLinkMaxSpeeds maps the values returned by GetPcieLinkMaxSpeed to MBPS
+var pcieLinkMaxSpeeds = map[uint32]float64{
+	nvml.PCIE_LINK_MAX_SPEED_2500MBPS:  2500,
+	nvml.PCIE_LINK_MAX_SPEED_5000MBPS:  5000,
+	nvml.PCIE_LINK_MAX_SPEED_8000MBPS:  8000,
+	nvml.PCIE_LINK_MAX_SPEED_16000MBPS: 16000,
+	nvml.PCIE_LINK_MAX_SPEED_32000MBPS: 32000,
+	nvml.PCIE_LINK_MAX_SPEED_64000MBPS: 64000,
+}
+
+type pcieCollector struct {
+	labels        []string
+	pcieTxBytes   *prometheus.Desc
+	pcieRxBytes   *prometheus.Desc
+	linkGen       *prometheus.Desc
+	linkGenMax    *prometheus.Desc
+	linkGenGpuMax *prometheus.Desc
+	linkWidth     *prometheus.Desc
+	linkWidthMax  *prometheus.Desc
+	linkSpeed     *prometheus.Desc
+	linkSpeedMax  *prometheus.Desc
+	replays       *prometheus.Desc
+	linkDegraded  *prometheus.Desc
+}
+
+func init() {
+	registerCollector("pcie", true, newPcieCollector)
+}
+
+func newPcieCollector(cfg collectorConfig) Collector {
+	return &pcieCollector{
+		labels:        cfg.DeviceLabels,
+		pcieTxBytes:   newDeviceDesc(cfg, "pcie_tx_bytes_per_second", "PCIe TX throughput in bytes per second"),
+		pcieRxBytes:   newDeviceDesc(cfg, "pcie_rx_bytes_per_second", "PCIe RX throughput in bytes per second"),
+		linkGen:       newDeviceDesc(cfg, "pcie_link_gen_current", "Current PCIe link generation"),
+		linkGenMax:    newDeviceDesc(cfg, "pcie_link_gen_max", "Maximum PCIe link generation supported by both the device and the system"),
+		linkGenGpuMax: newDeviceDesc(cfg, "pcie_link_gen_gpu_max", "Maximum PCIe link generation supported by the device"),
+		linkWidth:     newDeviceDesc(cfg, "pcie_link_width_current", "Current PCIe link width"),
+		linkWidthMax:  newDeviceDesc(cfg, "pcie_link_width_max", "Maximum PCIe link width supported by the device"),
+		linkSpeed:     newDeviceDesc(cfg, "pcie_link_speed_mbps", "Current PCIe link speed in MBPS"),
+		linkSpeedMax:  newDeviceDesc(cfg, "pcie_link_speed_max_mbps", "Maximum PCIe link speed in MBPS"),
+		replays:       newDeviceDesc(cfg, "pcie_replays_total", "Number of PCIe replays"),
+		linkDegraded:  newDeviceDesc(cfg, "pcie_link_degraded", "Whether the device is busy while its PCIe link runs below the maximum width or generation"),
+	}
+}
+
+func (c *pcieCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	// NVML reports throughput in KB/s
+	pcieTxBytes, ret := device.GetPcieThroughput(nvml.PCIE_UTIL_TX_BYTES)
+	if d.check("GetPcieThroughput", ret, "pcie_tx_bytes_per_second") {
+		d.PcieTxBytes = float64(pcieTxBytes) * 1024
+	}
+	pcieRxBytes, ret := device.GetPcieThroughput(nvml.PCIE_UTIL_RX_BYTES)
+	if d.check("GetPcieThroughput", ret, "pcie_rx_bytes_per_second") {
+		d.PcieRxBytes = float64(pcieRxBytes) * 1024
+	}
+
+	linkGen, ret := device.GetCurrPcieLinkGeneration()
+	if d.check("GetCurrPcieLinkGeneration", ret, "pcie_link_gen_current") {
+		d.PcieLinkGen = float64(linkGen)
+	}
+	linkGenMax, ret := device.GetMaxPcieLinkGeneration()
+	if d.check("GetMaxPcieLinkGeneration", ret, "pcie_link_gen_max") {
+		d.PcieLinkGenMax = float64(linkGenMax)
+	}
+	linkGenGpuMax, ret := device.GetGpuMaxPcieLinkGeneration()
+	if d.check("GetGpuMaxPcieLinkGeneration", ret, "pcie_link_gen_gpu_max") {
+		d.PcieLinkGenGpuMax = float64(linkGenGpuMax)
+	}
+	linkWidth, ret := device.GetCurrPcieLinkWidth()
+	if d.check("GetCurrPcieLinkWidth", ret, "pcie_link_width_current") {
+		d.PcieLinkWidth = float64(linkWidth)
+	}
+	linkWidthMax, ret := device.GetMaxPcieLinkWidth()
+	if d.check("GetMaxPcieLinkWidth", ret, "pcie_link_width_max") {
+		d.PcieLinkWidthMax = float64(linkWidthMax)
+	}
+	linkSpeed, ret := device.GetPcieSpeed()
+	if d.check("GetPcieSpeed", ret, "pcie_link_speed_mbps") {
+		d.PcieLinkSpeed = float64(linkSpeed)
+	}
+	linkSpeedMax, ret := device.GetPcieLinkMaxSpeed()
+	if ret == nvml.SUCCESS && pcieLinkMaxSpeeds[linkSpeedMax] == 0 {
+		ret = nvml.ERROR_UNKNOWN
+	}
+	if d.check("GetPcieLinkMaxSpeed", ret, "pcie_link_speed_max_mbps") {
+		d.PcieLinkSpeedMax = pcieLinkMaxSpeeds[linkSpeedMax]
+	}
+	replays, ret := device.GetPcieReplayCounter()
+	if d.check("GetPcieReplayCounter", ret, "pcie_replays_total") {
+		d.PcieReplays = float64(replays)
+	}
+
+	if !d.Supported["pcie_link_gen_current"] || !d.Supported["pcie_link_gen_max"] ||
+		!d.Supported["pcie_link_width_current"] || !d.Supported["pcie_link_width_max"] {
+		return nil
+	}
+	utilization, ok := d.utilizationRates(device)
+	d.setSupported(ok, "pcie_link_degraded")
+	if ok {
+		loaded := utilization.Gpu >= pcieLoadedUtilization
+		d.PcieLinkDegraded = boolFloat(loaded &&
+			(d.PcieLinkGen < d.PcieLinkGenMax || d.PcieLinkWidth < d.PcieLinkWidthMax))
+	}
+	return nil
+}
+
+func (c *pcieCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pcieTxBytes
+	ch <- c.pcieRxBytes
+	ch <- c.linkGen
+	ch <- c.linkGenMax
+	ch <- c.linkGenGpuMax
+	ch <- c.linkWidth
+	ch <- c.linkWidthMax
+	ch <- c.linkSpeed
+	ch <- c.linkSpeedMax
+	ch <- c.replays
+	ch <- c.linkDegraded
+}
+
+func (c *pcieCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.pcieTxBytes, "pcie_tx_bytes_per_second", d.PcieTxBytes, labels...)
+	deviceGauge(ch, d, c.pcieRxBytes, "pcie_rx_bytes_per_second", d.PcieRxBytes, labels...)
+	deviceGauge(ch, d, c.linkGen, "pcie_link_gen_current", d.PcieLinkGen, labels...)
+	deviceGauge(ch, d, c.linkGenMax, "pcie_link_gen_max", d.PcieLinkGenMax, labels...)
+	deviceGauge(ch, d, c.linkGenGpuMax, "pcie_link_gen_gpu_max", d.PcieLinkGenGpuMax, labels...)
+	deviceGauge(ch, d, c.linkWidth, "pcie_link_width_current", d.PcieLinkWidth, labels...)
+	deviceGauge(ch, d, c.linkWidthMax, "pcie_link_width_max", d.PcieLinkWidthMax, labels...)
+	deviceGauge(ch, d, c.linkSpeed, "pcie_link_speed_mbps", d.PcieLinkSpeed, labels...)
+	deviceGauge(ch, d, c.linkSpeedMax, "pcie_link_speed_max_mbps", d.PcieLinkSpeedMax, labels...)
+	if d.Supported["pcie_replays_total"] {
+		ch <- prometheus.MustNewConstMetric(c.replays, prometheus.CounterValue, d.PcieReplays, labels...)
+	}
+	deviceGauge(ch, d, c.linkDegraded, "pcie_link_degraded", d.PcieLinkDegraded, labels...)
+}
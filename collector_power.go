@@ -0,0 +1,157 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// powerSources maps the values returned by GetPowerSource to label values
+var powerSources = map[nvml.PowerSource]string{
+	nvml.POWER_SOURCE_AC:         "ac",
+	nvml.POWER_SOURCE_BATTERY:    "battery",
+	nvml.POWER_SOURCE_UNDERSIZED: "undersized",
+}
+
+type powerCollector struct {
+	labels          []string
+	powerUsage      *prometheus.Desc
+	enforcedLimit   *prometheus.Desc
+	managementLimit *prometheus.Desc
+	defaultLimit    *prometheus.Desc
+	limitMin        *prometheus.Desc
+	limitMax        *prometheus.Desc
+	limitModified   *prometheus.Desc
+	managementMode  *prometheus.Desc
+	powerSource     *prometheus.Desc
+	powerState      *prometheus.Desc
+	energy          *prometheus.Desc
+	powerInstant    *prometheus.Desc
+	powerAverage    *prometheus.Desc
+}
+
+func init() {
+	registerCollector("power", true, newPowerCollector)
+}
+
+func newPowerCollector(cfg collectorConfig) Collector {
+	return &powerCollector{
+		labels:          cfg.DeviceLabels,
+		powerUsage:      newDeviceDesc(cfg, "power_usage_watts", "Power usage as reported by GetPowerUsage"),
+		enforcedLimit:   newDeviceDesc(cfg, "power_enforced_limit_watts", "Power limit enforced by the driver, the lowest of all limits set"),
+		managementLimit: newDeviceDesc(cfg, "power_management_limit_watts", "Power limit set on the device"),
+		defaultLimit:    newDeviceDesc(cfg, "power_default_limit_watts", "Power limit the device has after the driver is loaded"),
+		limitMin:        newDeviceDesc(cfg, "power_limit_min_watts", "Minimum power limit that can be set"),
+		limitMax:        newDeviceDesc(cfg, "power_limit_max_watts", "Maximum power limit that can be set"),
+		limitModified:   newDeviceDesc(cfg, "power_limit_modified", "1 if the power limit differs from the default limit"),
+		managementMode:  newDeviceDesc(cfg, "power_management_mode", "1 if power management is enabled"),
+		powerSource:     newDeviceDesc(cfg, "power_source", "Source the device is powered from", "source"),
+		powerState:      newDeviceDesc(cfg, "power_state", "Power state of the device, 0 (maximum performance) to 15 (minimum performance)"),
+		energy:          newDeviceDesc(cfg, "energy_joules_total", "Energy consumed since the driver was last loaded"),
+		powerInstant:    newDeviceDesc(cfg, "power_instant_watts", "Instantaneous power draw"),
+		powerAverage:    newDeviceDesc(cfg, "power_average_watts", "Power draw averaged over the last second"),
+	}
+}
+
+func (c *powerCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	powerUsage, ret := device.GetPowerUsage()
+	if d.check("GetPowerUsage", ret, "power_usage_watts") {
+		d.PowerUsageWatts = float64(powerUsage) / 1000
+	}
+	enforcedLimit, ret := device.GetEnforcedPowerLimit()
+	if d.check("GetEnforcedPowerLimit", ret, "power_enforced_limit_watts") {
+		d.PowerEnforcedLimitWatts = float64(enforcedLimit) / 1000
+	}
+	managementLimit, ret := device.GetPowerManagementLimit()
+	if d.check("GetPowerManagementLimit", ret, "power_management_limit_watts") {
+		d.PowerManagementLimitWatts = float64(managementLimit) / 1000
+	}
+	defaultLimit, ret := device.GetPowerManagementDefaultLimit()
+	if d.check("GetPowerManagementDefaultLimit", ret, "power_default_limit_watts", "power_limit_modified") {
+		d.PowerDefaultLimitWatts = float64(defaultLimit) / 1000
+		// The flag needs the limit which is set as well
+		d.Supported["power_limit_modified"] = d.Supported["power_management_limit_watts"]
+		d.PowerLimitModified = boolFloat(managementLimit != defaultLimit)
+	}
+	minLimit, maxLimit, ret := device.GetPowerManagementLimitConstraints()
+	if d.check("GetPowerManagementLimitConstraints", ret, "power_limit_min_watts", "power_limit_max_watts") {
+		d.PowerLimitMinWatts = float64(minLimit) / 1000
+		d.PowerLimitMaxWatts = float64(maxLimit) / 1000
+	}
+	mode, ret := device.GetPowerManagementMode()
+	if d.check("GetPowerManagementMode", ret, "power_management_mode") {
+		d.PowerManagementMode = boolFloat(mode == nvml.FEATURE_ENABLED)
+	}
+	source, ret := device.GetPowerSource()
+	if d.check("GetPowerSource", ret, "power_source") {
+		d.PowerSource = powerSources[source]
+	}
+	state, ret := device.GetPowerState()
+	if ret == nvml.SUCCESS && state == nvml.PSTATE_UNKNOWN {
+		ret = nvml.ERROR_NOT_SUPPORTED
+	}
+	if d.check("GetPowerState", ret, "power_state") {
+		d.PowerState = float64(state)
+	}
+	energy, ret := device.GetTotalEnergyConsumption()
+	if d.check("GetTotalEnergyConsumption", ret, "energy_joules_total") {
+		d.EnergyJoules = float64(energy) / 1000
+	}
+
+	// GetPowerUsage is averaged on some devices and instantaneous on others,
+	// the fields say which is which
+	if v, ok := d.fieldValue(nvml.FI_DEV_POWER_INSTANT, 0, "power_instant_watts"); ok {
+		milliwatts, ok := fieldValue(v)
+		d.setSupported(ok, "power_instant_watts")
+		d.PowerInstantWatts = milliwatts / 1000
+	}
+	if v, ok := d.fieldValue(nvml.FI_DEV_POWER_AVERAGE, 0, "power_average_watts"); ok {
+		milliwatts, ok := fieldValue(v)
+		d.setSupported(ok, "power_average_watts")
+		d.PowerAverageWatts = milliwatts / 1000
+	}
+	return nil
+}
+
+func (c *powerCollector) Fields() []nvml.FieldValue {
+	return []nvml.FieldValue{
+		{FieldId: nvml.FI_DEV_POWER_INSTANT},
+		{FieldId: nvml.FI_DEV_POWER_AVERAGE},
+	}
+}
+
+func (c *powerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.powerUsage
+	ch <- c.enforcedLimit
+	ch <- c.managementLimit
+	ch <- c.defaultLimit
+	ch <- c.limitMin
+	ch <- c.limitMax
+	ch <- c.limitModified
+	ch <- c.managementMode
+	ch <- c.powerSource
+	ch <- c.powerState
+	ch <- c.energy
+	ch <- c.powerInstant
+	ch <- c.powerAverage
+}
+
+func (c *powerCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.powerUsage, "power_usage_watts", d.PowerUsageWatts, labels...)
+	deviceGauge(ch, d, c.enforcedLimit, "power_enforced_limit_watts", d.PowerEnforcedLimitWatts, labels...)
+	deviceGauge(ch, d, c.managementLimit, "power_management_limit_watts", d.PowerManagementLimitWatts, labels...)
+	deviceGauge(ch, d, c.defaultLimit, "power_default_limit_watts", d.PowerDefaultLimitWatts, labels...)
+	deviceGauge(ch, d, c.limitModified, "power_limit_modified", d.PowerLimitModified, labels...)
+	deviceGauge(ch, d, c.limitMin, "power_limit_min_watts", d.PowerLimitMinWatts, labels...)
+	deviceGauge(ch, d, c.limitMax, "power_limit_max_watts", d.PowerLimitMaxWatts, labels...)
+	deviceGauge(ch, d, c.managementMode, "power_management_mode", d.PowerManagementMode, labels...)
+	if d.Supported["power_source"] && d.PowerSource != "" {
+		ch <- prometheus.MustNewConstMetric(c.powerSource, prometheus.GaugeValue, 1, d.labelValues(c.labels, d.PowerSource)...)
+	}
+	deviceGauge(ch, d, c.powerState, "power_state", d.PowerState, labels...)
+	if d.Supported["energy_joules_total"] {
+		ch <- prometheus.MustNewConstMetric(c.energy, prometheus.CounterValue, d.EnergyJoules, labels...)
+	}
+	deviceGauge(ch, d, c.powerInstant, "power_instant_watts", d.PowerInstantWatts, labels...)
+	deviceGauge(ch, d, c.powerAverage, "power_average_watts", d.PowerAverageWatts, labels...)
+}
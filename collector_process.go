@@ -0,0 +1,129 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// processMetrics are the metrics backed by GetProcessUtilization
+var processMetrics = []string{
+	"utilization_process_name",
+	"utilization_process_smutil",
+	"utilization_process_memutil",
+	"utilization_process_encutil",
+	"utilization_process_decutil",
+}
+
+type processCollector struct {
+	labels                    []string
+	stripProcessArgs          bool
+	stripProcessPath          bool
+	utilizationProcessName    *prometheus.Desc
+	utilizationProcessSMUtil  *prometheus.Desc
+	utilizationProcessMemUtil *prometheus.Desc
+	utilizationProcessEncUtil *prometheus.Desc
+	utilizationProcessDecUtil *prometheus.Desc
+}
+
+func init() {
+	registerCollector("process", false, newProcessCollector)
+}
+
+func newProcessCollector(cfg collectorConfig) Collector {
+	return &processCollector{
+		labels:                    cfg.DeviceLabels,
+		stripProcessArgs:          cfg.StripProcessArgs,
+		stripProcessPath:          cfg.StripProcessPath,
+		utilizationProcessName:    newDeviceDesc(cfg, "utilization_process_name", "Process name, if value is 0 the name couldn't be determined", "pid", "name"),
+		utilizationProcessSMUtil:  newDeviceDesc(cfg, "utilization_process_smutil", "Process SM utilization stats averaged over 10s, not available on GPUs in MIG mode", "pid"),
+		utilizationProcessMemUtil: newDeviceDesc(cfg, "utilization_process_memutil", "Process memory utilization stats averaged over 10s, not available on GPUs in MIG mode", "pid"),
+		utilizationProcessEncUtil: newDeviceDesc(cfg, "utilization_process_encutil", "Process encoder utilization stats averaged over 10s, not available on GPUs in MIG mode", "pid"),
+		utilizationProcessDecUtil: newDeviceDesc(cfg, "utilization_process_decutil", "Process decoder utilization stats averaged over 10s, not available on GPUs in MIG mode", "pid"),
+	}
+}
+
+func (c *processCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	utilizations, ret := device.GetProcessUtilization(10)
+	// NOT_FOUND only means there were no samples since the last call
+	if ret == nvml.ERROR_NOT_FOUND {
+		ret = nvml.SUCCESS
+	}
+	// NVML doesn't sample processes on a GPU in MIG mode and returns
+	// NOT_SUPPORTED, nor does it take MIG device handles
+	if !d.check("GetProcessUtilization", ret, processMetrics...) {
+		if ret == nvml.ERROR_NOT_SUPPORTED {
+			return nil
+		}
+		log.Errorf("\tfailed to get process utilization for GPU %s: %v", d.Index, ret)
+		return nil
+	}
+	log.Debugf("process count: %d", len(utilizations))
+	// A process can have several samples, only the newest is exported
+	latest := make(map[uint32]int)
+	var samples []nvml.ProcessUtilizationSample
+	for _, sample := range utilizations {
+		i, ok := latest[sample.Pid]
+		if !ok {
+			latest[sample.Pid] = len(samples)
+			samples = append(samples, sample)
+		} else if sample.TimeStamp > samples[i].TimeStamp {
+			samples[i] = sample
+		}
+	}
+	var pList []*Process
+	for _, sample := range samples {
+		var p = Process{
+			PID:     sample.Pid,
+			SMUtil:  sample.SmUtil,
+			MemUtil: sample.MemUtil,
+			EncUtil: sample.EncUtil,
+			DecUtil: sample.DecUtil,
+		}
+
+		name, ret := lib.SystemGetProcessName(int(sample.Pid))
+		if !d.check("SystemGetProcessName", ret) {
+			log.Debugf("\tfailed to get process name for PID %d: %v\n", sample.Pid, ret)
+		} else {
+			if c.stripProcessArgs {
+				name = strings.Split(name, " ")[0]
+			}
+			if c.stripProcessPath {
+				name = filepath.Base(name)
+			}
+			p.Name = &name
+		}
+		pList = append(pList, &p)
+		log.Debug(p.ToString())
+	}
+	d.UtilizationProcesses = pList
+	return nil
+}
+
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.utilizationProcessName
+	ch <- c.utilizationProcessSMUtil
+	ch <- c.utilizationProcessMemUtil
+	ch <- c.utilizationProcessEncUtil
+	ch <- c.utilizationProcessDecUtil
+}
+
+func (c *processCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	}
+	for _, p := range d.UtilizationProcesses {
+		if p.Name != nil {
+			gauge(c.utilizationProcessName, 1, d.labelValues(c.labels, p.PromPID(), *p.Name)...)
+		} else {
+			gauge(c.utilizationProcessName, 0, d.labelValues(c.labels, p.PromPID(), "N/A")...)
+		}
+		gauge(c.utilizationProcessSMUtil, float64(p.SMUtil), d.labelValues(c.labels, p.PromPID())...)
+		gauge(c.utilizationProcessMemUtil, float64(p.MemUtil), d.labelValues(c.labels, p.PromPID())...)
+		gauge(c.utilizationProcessEncUtil, float64(p.EncUtil), d.labelValues(c.labels, p.PromPID())...)
+		gauge(c.utilizationProcessDecUtil, float64(p.DecUtil), d.labelValues(c.labels, p.PromPID())...)
+	}
+}
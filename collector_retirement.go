@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// retirementCauses maps page retirement causes to label values
+var retirementCauses = []struct {
+	cause nvml.PageRetirementCause
+	name  string
+}{
+	{nvml.PAGE_RETIREMENT_CAUSE_MULTIPLE_SINGLE_BIT_ECC_ERRORS, "multiple_single_bit_ecc"},
+	{nvml.PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR, "double_bit_ecc"},
+}
+
+// remappedRowsMetrics are the metrics backed by GetRemappedRows
+var remappedRowsMetrics = []string{
+	"remapped_rows",
+	"remapped_rows_pending",
+	"remapped_rows_failure",
+}
+
+type retirementCollector struct {
+	labels                  []string
+	retiredPages            *prometheus.Desc
+	retiredPagesPending     *prometheus.Desc
+	remappedRows            *prometheus.Desc
+	remappedRowsPending     *prometheus.Desc
+	remappedRowsFailure     *prometheus.Desc
+	rowRemapperAvailability *prometheus.Desc
+}
+
+func init() {
+	registerCollector("retirement", true, newRetirementCollector)
+}
+
+func newRetirementCollector(cfg collectorConfig) Collector {
+	return &retirementCollector{
+		labels:                  cfg.DeviceLabels,
+		retiredPages:            newDeviceDesc(cfg, "retired_pages", "Number of memory pages retired by cause", "cause"),
+		retiredPagesPending:     newDeviceDesc(cfg, "retired_pages_pending", "Whether pages are waiting to be retired on the next reset"),
+		remappedRows:            newDeviceDesc(cfg, "remapped_rows", "Number of memory rows remapped because of corrected or uncorrected errors", "type"),
+		remappedRowsPending:     newDeviceDesc(cfg, "remapped_rows_pending", "Whether rows are waiting to be remapped on the next reset"),
+		remappedRowsFailure:     newDeviceDesc(cfg, "remapped_rows_failure", "Whether a row remapping failed, the GPU should be replaced"),
+		rowRemapperAvailability: newDeviceDesc(cfg, "row_remapper_availability_banks", "Number of memory banks by how many remapping rows they have left", "availability"),
+	}
+}
+
+func (c *retirementCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	// Page retirement is replaced by row remapping from Ampere onwards
+	for _, cause := range retirementCauses {
+		pages, ret := device.GetRetiredPages(cause.cause)
+		if !d.check("GetRetiredPages", ret, "retired_pages") {
+			break
+		}
+		if d.RetiredPages == nil {
+			d.RetiredPages = make(map[string]float64)
+		}
+		d.RetiredPages[cause.name] = float64(len(pages))
+	}
+	pending, ret := device.GetRetiredPagesPendingStatus()
+	if d.check("GetRetiredPagesPendingStatus", ret, "retired_pages_pending") {
+		d.RetiredPagesPending = boolFloat(pending == nvml.FEATURE_ENABLED)
+	}
+
+	corrRows, uncRows, isPending, failureOccurred, ret := device.GetRemappedRows()
+	if d.check("GetRemappedRows", ret, remappedRowsMetrics...) {
+		d.RemappedRowsCorrected = float64(corrRows)
+		d.RemappedRowsUncorrected = float64(uncRows)
+		d.RemappedRowsPending = boolFloat(isPending)
+		d.RemappedRowsFailure = boolFloat(failureOccurred)
+	}
+	histogram, ret := device.GetRowRemapperHistogram()
+	if d.check("GetRowRemapperHistogram", ret, "row_remapper_availability_banks") {
+		d.RowRemapperHistogram = histogram
+	}
+	return nil
+}
+
+func (c *retirementCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.retiredPages
+	ch <- c.retiredPagesPending
+	ch <- c.remappedRows
+	ch <- c.remappedRowsPending
+	ch <- c.remappedRowsFailure
+	ch <- c.rowRemapperAvailability
+}
+
+func (c *retirementCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	if d.Supported["retired_pages"] {
+		for cause, count := range d.RetiredPages {
+			ch <- prometheus.MustNewConstMetric(c.retiredPages, prometheus.GaugeValue, count, d.labelValues(c.labels, cause)...)
+		}
+	}
+	deviceGauge(ch, d, c.retiredPagesPending, "retired_pages_pending", d.RetiredPagesPending, labels...)
+	deviceGauge(ch, d, c.remappedRows, "remapped_rows", d.RemappedRowsCorrected, d.labelValues(c.labels, "corrected")...)
+	deviceGauge(ch, d, c.remappedRows, "remapped_rows", d.RemappedRowsUncorrected, d.labelValues(c.labels, "uncorrected")...)
+	deviceGauge(ch, d, c.remappedRowsPending, "remapped_rows_pending", d.RemappedRowsPending, labels...)
+	deviceGauge(ch, d, c.remappedRowsFailure, "remapped_rows_failure", d.RemappedRowsFailure, labels...)
+	if d.Supported["row_remapper_availability_banks"] {
+		h := d.RowRemapperHistogram
+		for _, bucket := range []struct {
+			name  string
+			banks uint32
+		}{
+			{"max", h.Max},
+			{"high", h.High},
+			{"partial", h.Partial},
+			{"low", h.Low},
+			{"none", h.None},
+		} {
+			ch <- prometheus.MustNewConstMetric(c.rowRemapperAvailability, prometheus.GaugeValue, float64(bucket.banks),
+				d.labelValues(c.labels, bucket.name)...)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// temperatureThresholds maps the thresholds exported by the temperature
+// collector to label values
+var temperatureThresholds = []struct {
+	threshold nvml.TemperatureThresholds
+	name      string
+}{
+	{nvml.TEMPERATURE_THRESHOLD_SHUTDOWN, "shutdown"},
+	{nvml.TEMPERATURE_THRESHOLD_SLOWDOWN, "slowdown"},
+	{nvml.TEMPERATURE_THRESHOLD_MEM_MAX, "memory_max"},
+	{nvml.TEMPERATURE_THRESHOLD_GPU_MAX, "gpu_max"},
+}
+
+// thermalTargets maps what a thermal sensor measures to label values
+var thermalTargets = map[nvml.ThermalTarget]string{
+	nvml.THERMAL_TARGET_NONE:         "none",
+	nvml.THERMAL_TARGET_GPU:          "gpu",
+	nvml.THERMAL_TARGET_MEMORY:       "memory",
+	nvml.THERMAL_TARGET_POWER_SUPPLY: "power_supply",
+	nvml.THERMAL_TARGET_BOARD:        "board",
+	nvml.THERMAL_TARGET_VCD_BOARD:    "vcd_board",
+	nvml.THERMAL_TARGET_VCD_INLET:    "vcd_inlet",
+	nvml.THERMAL_TARGET_VCD_OUTLET:   "vcd_outlet",
+	nvml.THERMAL_TARGET_ALL:          "all",
+	nvml.THERMAL_TARGET_UNKNOWN:      "unknown",
+}
+
+// ThermalSensor is a single reading of GetThermalSettings
+type ThermalSensor struct {
+	Sensor      string
+	Target      string
+	Temperature float64
+}
+
+type temperatureCollector struct {
+	labels            []string
+	temperature       *prometheus.Desc
+	threshold         *prometheus.Desc
+	memoryTemperature *prometheus.Desc
+	sensorTemperature *prometheus.Desc
+}
+
+func init() {
+	registerCollector("temperature", true, newTemperatureCollector)
+}
+
+func newTemperatureCollector(cfg collectorConfig) Collector {
+	return &temperatureCollector{
+		labels:            cfg.DeviceLabels,
+		temperature:       newDeviceDesc(cfg, "temperatures", "Temperature as reported by the device"),
+		threshold:         newDeviceDesc(cfg, "temperature_threshold_celsius", "Temperature at which the device slows down or shuts down, or the maximum for the GPU or memory", "threshold"),
+		memoryTemperature: newDeviceDesc(cfg, "memory_temperature_celsius", "Temperature of the device memory"),
+		sensorTemperature: newDeviceDesc(cfg, "thermal_sensor_temperature_celsius", "Temperature of each thermal sensor of the device", "sensor", "target"),
+	}
+}
+
+func (c *temperatureCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	temperature, ret := device.GetTemperature(nvml.TEMPERATURE_GPU)
+	if d.check("GetTemperature", ret, "temperatures") {
+		d.Temperature = float64(temperature)
+	}
+
+	d.TemperatureThresholds = make(map[string]float64)
+	for _, t := range temperatureThresholds {
+		threshold, ret := device.GetTemperatureThreshold(t.threshold)
+		// Most devices only have some of the thresholds
+		if ret == nvml.ERROR_NOT_SUPPORTED {
+			continue
+		}
+		if d.check("GetTemperatureThreshold", ret) {
+			d.TemperatureThresholds[t.name] = float64(threshold)
+		}
+	}
+
+	if v, ok := d.fieldValue(nvml.FI_DEV_MEMORY_TEMP, 0, "memory_temperature_celsius"); ok {
+		var ok bool
+		d.MemoryTemperature, ok = fieldValue(v)
+		d.setSupported(ok, "memory_temperature_celsius")
+	}
+
+	settings, ret := device.GetThermalSettings(uint32(nvml.THERMAL_TARGET_ALL))
+	if d.check("GetThermalSettings", ret, "thermal_sensor_temperature_celsius") {
+		for i, sensor := range settings.Sensor[:min(int(settings.Count), len(settings.Sensor))] {
+			d.ThermalSensors = append(d.ThermalSensors, ThermalSensor{
+				Sensor:      strconv.Itoa(i),
+				Target:      thermalTargets[nvml.ThermalTarget(sensor.Target)],
+				Temperature: float64(sensor.CurrentTemp),
+			})
+		}
+	}
+	return nil
+}
+
+func (c *temperatureCollector) Fields() []nvml.FieldValue {
+	return []nvml.FieldValue{{FieldId: nvml.FI_DEV_MEMORY_TEMP}}
+}
+
+func (c *temperatureCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.temperature
+	ch <- c.threshold
+	ch <- c.memoryTemperature
+	ch <- c.sensorTemperature
+}
+
+func (c *temperatureCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.temperature, "temperatures", d.Temperature, labels...)
+	for name, threshold := range d.TemperatureThresholds {
+		ch <- prometheus.MustNewConstMetric(c.threshold, prometheus.GaugeValue, threshold, d.labelValues(c.labels, name)...)
+	}
+	deviceGauge(ch, d, c.memoryTemperature, "memory_temperature_celsius", d.MemoryTemperature, labels...)
+	for _, s := range d.ThermalSensors {
+		ch <- prometheus.MustNewConstMetric(c.sensorTemperature, prometheus.GaugeValue, s.Temperature,
+			d.labelValues(c.labels, s.Sensor, s.Target)...)
+	}
+}
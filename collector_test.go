@@ -0,0 +1,290 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCollectors(t *testing.T) {
+	tests := []struct {
+		name       string
+		fixture    string
+		configFile string
+		want       map[string]float64
+		// missing are samples which must not be exported
+		missing []string
+	}{
+		{
+			name:    "power",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_power_usage_watts{minor="0"}`:          31.037,
+				`nvidia_power_enforced_limit_watts{minor="0"}`: 200,
+				`nvidia_power_average_watts{minor="0"}`:        31.037,
+				`nvidia_power_enforced_limit_watts{minor="1"}`: 700,
+			},
+			missing: []string{`nvidia_power_average_watts{minor="1"}`},
+		},
+		{
+			name:    "temperature",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_temperatures{minor="0"}`:               51,
+				`nvidia_memory_temperature_celsius{minor="1"}`: 44,
+			},
+			missing: []string{`nvidia_memory_temperature_celsius{minor="0"}`},
+		},
+		{
+			name:    "process keeps the newest sample",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_utilization_process_smutil{minor="0",pid="2114"}`: 14,
+				`nvidia_utilization_process_smutil{minor="0",pid="3136"}`: 1,
+			},
+		},
+		{
+			name:    "pcie",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_pcie_tx_bytes_per_second{minor="0"}`: 1.1776e+06,
+				`nvidia_pcie_rx_bytes_per_second{minor="0"}`: 870400,
+			},
+		},
+		{
+			name:    "clock events",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_clock_event_reason_active{minor="0",reason="sw_power_cap"}`:   1,
+				`nvidia_clock_event_reason_active{minor="0",reason="hw_power_brake"}`: 0,
+			},
+		},
+		{
+			name:    "ecc",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_ecc_mode_current{minor="1"}`: 1,
+				`nvidia_ecc_errors_total{counter="aggregate",location="device_memory",minor="1",type="corrected"}`: 14,
+				`nvidia_ecc_errors_total{counter="volatile",location="all",minor="1",type="corrected"}`:            2,
+			},
+			missing: []string{`nvidia_ecc_mode_current{minor="0"}`},
+		},
+		{
+			name:    "retirement",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_remapped_rows{minor="1",type="corrected"}`:                     2,
+				`nvidia_row_remapper_availability_banks{availability="max",minor="1"}`: 638,
+			},
+			missing: []string{`nvidia_remapped_rows{minor="0",type="corrected"}`},
+		},
+		{
+			name:    "nvlink",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_nvlink_errors_total{counter="replay",link="0",minor="1"}`:                                              3,
+				`nvidia_nvlink_received_bytes_total{link="0",minor="1"}`:                                                       8.192e+07,
+				`nvidia_nvlink_info{link="1",minor="1",remote_pci_bus_id="00000000:C2:00.0",remote_type="switch",version="4"}`: 1,
+			},
+		},
+		{
+			name:    "violation",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_violation_seconds_total{minor="1",policy="power"}`:   1843.25,
+				`nvidia_violation_seconds_total{minor="1",policy="thermal"}`: 12.5,
+			},
+			missing: []string{`nvidia_violation_seconds_total{minor="0",policy="power"}`},
+		},
+		{
+			name:    "fan",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_fanspeed{minor="0"}`:                                 30,
+				`nvidia_fan_speed_percent{fan="1",minor="0"}`:                0,
+				`nvidia_fan_target_speed_percent{fan="1",minor="0"}`:         30,
+				`nvidia_fan_control_policy{fan="0",minor="0",policy="auto"}`: 1,
+			},
+			missing: []string{`nvidia_fanspeed{minor="1"}`},
+		},
+		{
+			name:    "clocks",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_clock_current_video{minor="1"}`:                       765,
+				`nvidia_clock_max{clock="memory",minor="0"}`:                  10501,
+				`nvidia_clock_applications{clock="graphics",minor="1"}`:       1755,
+				`nvidia_clock_pstate_min{clock="graphics",minor="0"}`:         210,
+				`nvidia_clock_default_applications{clock="memory",minor="1"}`: 2619,
+			},
+			missing: []string{`nvidia_clock_applications{clock="graphics",minor="0"}`},
+		},
+		{
+			name:    "encoder",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_encoder_sessions{minor="0"}`:                     2,
+				`nvidia_encoder_average_latency_seconds{minor="0"}`:      0.000735,
+				`nvidia_encoder_capacity_percent{codec="av1",minor="0"}`: 84,
+				`nvidia_fbc_average_fps{minor="0"}`:                      60,
+			},
+		},
+		{
+			name:    "mig",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_mig_memory_used{compute_instance="0",gpu_instance="9",mig_uuid="MIG-2b7e4d19-0c3a-5f88-b6e1-7d9c0a2f3b41",minor="1",profile="1g.10gb"}`: 1.31072e+07,
+			},
+		},
+		{
+			name:    "gpm",
+			fixture: "examples/fake.yaml",
+			want: map[string]float64{
+				`nvidia_gpm_sm_activity_percent{minor="1"}`: 71.5,
+			},
+			missing: []string{`nvidia_gpm_sm_activity_percent{minor="0"}`},
+		},
+		{
+			name:       "fields",
+			fixture:    "examples/fake.yaml",
+			configFile: "examples/fields.yaml",
+			want: map[string]float64{
+				`nvidia_nvlink_data_transmitted_bytes_total{minor="1",scope="0"}`: 5.205738496e+09,
+				`nvidia_nvlink_data_transmitted_bytes_total{minor="1",scope="1"}`: 5.205738496e+09,
+			},
+		},
+		{
+			name:    "vgpu host",
+			fixture: "examples/vgpu.yaml",
+			want: map[string]float64{
+				`nvidia_vgpu_licensed{minor="0",vgpu_type="NVIDIA A16-4Q",vgpu_uuid="7c3f9a1e-2b4d-4e6f-8a0b-1c2d3e4f5a6b",vm_id="4b1e9a5f-0e6b-4c8d-a2f3-7d2c6e8b1a34"}`:       1,
+				`nvidia_vgpu_licensed{minor="0",vgpu_type="NVIDIA A16-4Q",vgpu_uuid="9d4e0b2f-3c5e-4f70-9b1c-2d3e4f5a6b7c",vm_id="5c2f0b6a-1f7c-4d9e-b3a4-8e3d7f9c2b45"}`:       0,
+				`nvidia_vgpu_utilization_sm{minor="0",vgpu_type="NVIDIA A16-4Q",vgpu_uuid="7c3f9a1e-2b4d-4e6f-8a0b-1c2d3e4f5a6b",vm_id="4b1e9a5f-0e6b-4c8d-a2f3-7d2c6e8b1a34"}`: 41,
+			},
+			missing: []string{`nvidia_vgpu_license_licensed{feature="",minor="0",vgpu_type="NVIDIA A16-4Q",vgpu_uuid="7c3f9a1e-2b4d-4e6f-8a0b-1c2d3e4f5a6b",vm_id="4b1e9a5f-0e6b-4c8d-a2f3-7d2c6e8b1a34"}`},
+		},
+		{
+			name:    "vgpu guest",
+			fixture: "examples/vgpu-guest.yaml",
+			want: map[string]float64{
+				`nvidia_vgpu_license_licensed{feature="nvidia_rtx",minor="0",vgpu_type="NVIDIA A16-4Q",vgpu_uuid="",vm_id=""}`: 1,
+				`nvidia_vgpu_license_licensed{feature="9",minor="0",vgpu_type="NVIDIA A16-4Q",vgpu_uuid="",vm_id=""}`:          0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileCfg, err := loadConfig(tt.configFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exporter := newTestExporter(t, tt.fixture, collectorConfig{Fields: fileCfg.Fields}, collectConfig{})
+			// GPM metrics need the sample of an earlier collection
+			scrape(t, exporter)
+			checkSamples(t, scrape(t, exporter), tt.want, tt.missing)
+		})
+	}
+}
+
+func TestCollectorFlags(t *testing.T) {
+	state := true
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&collectorFlag{state: &state, value: true}, "collector.test", "")
+	fs.Var(&collectorFlag{state: &state, value: false}, "no-collector.test", "")
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"--no-collector.test"}, want: false},
+		{args: []string{"--collector.test"}, want: true},
+		{args: []string{"--no-collector.test=false"}, want: true},
+		{args: []string{"--collector.test=false"}, want: false},
+		{args: []string{"--no-collector.test", "--collector.test"}, want: true},
+	} {
+		state = !tt.want
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if state != tt.want {
+			t.Errorf("%v: collector enabled %v, want %v", tt.args, state, tt.want)
+		}
+	}
+}
+
+func TestNewCollectors(t *testing.T) {
+	cfg := collectorConfig{DeviceLabels: []string{"minor"}}
+	if _, ok := newCollectors(cfg)["process"]; ok {
+		t.Error("process collector is enabled by default")
+	}
+	*collectorState["process"] = true
+	defer func() { *collectorState["process"] = false }()
+	*collectorState["fan"] = false
+	defer func() { *collectorState["fan"] = true }()
+	collectors := newCollectors(cfg)
+	if _, ok := collectors["process"]; !ok {
+		t.Error("enabled process collector is missing")
+	}
+	if _, ok := collectors["fan"]; ok {
+		t.Error("disabled fan collector was created")
+	}
+}
+
+func TestMetricsHandlerCollect(t *testing.T) {
+	exporter := newTestExporter(t, "examples/fake.yaml", collectorConfig{}, collectConfig{})
+	delete(exporter.collectors, "process")
+	handler := metricsHandler(exporter)
+	get := func(query string) (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics?"+query, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	code, body := get("collect[]=temperature&collect[]=fan")
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %s", code, body)
+	}
+	for _, want := range []string{
+		`nvidia_up{reason=""} 1`,
+		`nvidia_temperatures{minor="0"} 51`,
+		`nvidia_fanspeed{minor="0"} 30`,
+		`nvidia_scrape_collector_success{collector="temperature"} 1`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("%s is missing", want)
+		}
+	}
+	for _, unwanted := range []string{"nvidia_clock_current_graphics", `collector="clocks"`} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("%s is exported", unwanted)
+		}
+	}
+
+	for _, query := range []string{"collect[]=unknown", "collect[]=process"} {
+		if code, body := get(query); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d: %s", query, code, http.StatusBadRequest, body)
+		}
+	}
+}
+
+func TestCheckFieldNames(t *testing.T) {
+	exporter := newTestExporter(t, writeFixture(t, twoDevices), collectorConfig{}, collectConfig{})
+	names := append(collectorMetricNames(collectorConfig{DeviceLabels: []string{"minor"}}), exporter.metricNames...)
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "power_usage_watts", wantErr: true},
+		// The process collector is disabled by default
+		{name: "utilization_process_smutil", wantErr: true},
+		{name: "up", wantErr: true},
+		{name: "nvlink_data_transmitted_bytes_total"},
+	}
+	for _, tt := range tests {
+		err := checkFieldNames([]fieldConfig{{ID: 1, Name: tt.name}}, names)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkFieldNames(%q) error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
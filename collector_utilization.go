@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type utilizationCollector struct {
+	labels            []string
+	utilizationMemory *prometheus.Desc
+	utilizationGPU    *prometheus.Desc
+}
+
+func init() {
+	registerCollector("utilization", true, newUtilizationCollector)
+}
+
+func newUtilizationCollector(cfg collectorConfig) Collector {
+	return &utilizationCollector{
+		labels:            cfg.DeviceLabels,
+		utilizationMemory: newDeviceDesc(cfg, "utilization_memory", "Memory Utilization as reported by the device"),
+		utilizationGPU:    newDeviceDesc(cfg, "utilization_gpu", "GPU utilization as reported by the device"),
+	}
+}
+
+func (c *utilizationCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	utilizationRates, ok := d.utilizationRates(device)
+	d.setSupported(ok, "utilization_memory", "utilization_gpu")
+	if ok {
+		d.UtilizationMemory = float64(utilizationRates.Memory)
+		d.UtilizationGPU = float64(utilizationRates.Gpu)
+	}
+	return nil
+}
+
+func (c *utilizationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.utilizationMemory
+	ch <- c.utilizationGPU
+}
+
+func (c *utilizationCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	labels := d.labelValues(c.labels)
+	deviceGauge(ch, d, c.utilizationMemory, "utilization_memory", d.UtilizationMemory, labels...)
+	deviceGauge(ch, d, c.utilizationGPU, "utilization_gpu", d.UtilizationGPU, labels...)
+}
+
+// utilizationRates returns the utilization rates of device and whether they
+// could be read, they are read once per collection and shared by the
+// collectors which need them
+func (d *Device) utilizationRates(device nvml.Device) (nvml.Utilization, bool) {
+	if d.utilization == nil {
+		// Charge the call to the utilization collector whichever collector
+		// makes it first
+		current := d.current
+		if result, ok := d.Results["utilization"]; ok {
+			d.current = result
+		}
+		utilization, ret := device.GetUtilizationRates()
+		d.utilization = &utilization
+		d.utilizationOK = d.check("GetUtilizationRates", ret)
+		d.current = current
+	}
+	return *d.utilization, d.utilizationOK
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// virtualizationModes maps the values returned by GetVirtualizationMode to
+// label values
+var virtualizationModes = map[nvml.GpuVirtualizationMode]string{
+	nvml.GPU_VIRTUALIZATION_MODE_NONE:        "none",
+	nvml.GPU_VIRTUALIZATION_MODE_PASSTHROUGH: "passthrough",
+	nvml.GPU_VIRTUALIZATION_MODE_VGPU:        "vgpu",
+	nvml.GPU_VIRTUALIZATION_MODE_HOST_VGPU:   "host_vgpu",
+	nvml.GPU_VIRTUALIZATION_MODE_HOST_VSGA:   "host_vsga",
+}
+
+// gridLicenseStates maps the license states of vGPU instances to label values
+var gridLicenseStates = map[uint32]string{
+	nvml.GRID_LICENSE_STATE_UNKNOWN:                 "unknown",
+	nvml.GRID_LICENSE_STATE_UNINITIALIZED:           "uninitialized",
+	nvml.GRID_LICENSE_STATE_UNLICENSED_UNRESTRICTED: "unlicensed_unrestricted",
+	nvml.GRID_LICENSE_STATE_UNLICENSED_RESTRICTED:   "unlicensed_restricted",
+	nvml.GRID_LICENSE_STATE_UNLICENSED:              "unlicensed",
+	nvml.GRID_LICENSE_STATE_LICENSED:                "licensed",
+}
+
+// gridLicenseFeatures maps the licensable features of a guest to label values,
+// other features are labelled with their code
+var gridLicenseFeatures = map[uint32]string{
+	uint32(nvml.GRID_LICENSE_FEATURE_CODE_UNKNOWN):    "unknown",
+	uint32(nvml.GRID_LICENSE_FEATURE_CODE_VGPU):       "vgpu",
+	uint32(nvml.GRID_LICENSE_FEATURE_CODE_NVIDIA_RTX): "nvidia_rtx",
+	uint32(nvml.GRID_LICENSE_FEATURE_CODE_GAMING):     "gaming",
+	uint32(nvml.GRID_LICENSE_FEATURE_CODE_COMPUTE):    "compute",
+}
+
+// vgpuLabels are the labels of per-vGPU metrics, after the device labels
+var vgpuLabels = []string{"vgpu_uuid", "vm_id", "vgpu_type"}
+
+// licenseLabels are the labels of license metrics, on a host they identify
+// the vGPU instance and inside a guest only vgpu_type and feature are set
+var licenseLabels = withLabels(vgpuLabels, "feature")
+
+// Vgpu holds the state of a vGPU instance running on a host, values which
+// couldn't be read are negative
+type Vgpu struct {
+	UUID                  string
+	VMID                  string
+	Type                  string
+	FbUsage               float64
+	Licensed              float64
+	UtilizationSM         float64
+	UtilizationMemory     float64
+	UtilizationEncoder    float64
+	UtilizationDecoder    float64
+	EncoderSessions       float64
+	EncoderAverageFPS     float64
+	EncoderAverageLatency float64
+	FBCSessions           float64
+	FBCAverageFPS         float64
+	FBCAverageLatency     float64
+}
+
+// VgpuLicense is the license of a vGPU instance on a host, or of a
+// licensable feature inside a guest. Values which don't apply are negative.
+type VgpuLicense struct {
+	VgpuUUID string
+	VMID     string
+	Type     string
+	Feature  string
+	// State is only reported on hosts
+	State string
+	// Licensed is only reported inside guests, on hosts it is the
+	// Licensed field of the Vgpu
+	Licensed float64
+	// Enabled is only reported inside guests
+	Enabled float64
+	// Expiry is zero if the license doesn't expire or the expiry is unknown
+	Expiry        time.Time
+	DaysRemaining float64
+}
+
+type vgpuCollector struct {
+	labels                []string
+	virtualizationMode    *prometheus.Desc
+	info                  *prometheus.Desc
+	fbUsage               *prometheus.Desc
+	licensed              *prometheus.Desc
+	utilizationSM         *prometheus.Desc
+	utilizationMemory     *prometheus.Desc
+	utilizationEncoder    *prometheus.Desc
+	utilizationDecoder    *prometheus.Desc
+	encoderSessions       *prometheus.Desc
+	encoderAverageFPS     *prometheus.Desc
+	encoderAverageLatency *prometheus.Desc
+	fbcSessions           *prometheus.Desc
+	fbcAverageFPS         *prometheus.Desc
+	fbcAverageLatency     *prometheus.Desc
+	guestInfo             *prometheus.Desc
+	licenseLicensed       *prometheus.Desc
+	licenseState          *prometheus.Desc
+	licenseEnabled        *prometheus.Desc
+	licenseExpiry         *prometheus.Desc
+	licenseDaysRemaining  *prometheus.Desc
+}
+
+func init() {
+	registerCollector("vgpu", true, newVgpuCollector)
+}
+
+func newVgpuCollector(cfg collectorConfig) Collector {
+	return &vgpuCollector{
+		labels:                cfg.DeviceLabels,
+		virtualizationMode:    newDeviceDesc(cfg, "virtualization_mode", "Virtualization mode of the device", "mode"),
+		info:                  newDeviceDesc(cfg, "vgpu_info", "A metric with a constant '1' value labeled by vGPU instance", vgpuLabels...),
+		fbUsage:               newDeviceDesc(cfg, "vgpu_fb_usage_bytes", "Framebuffer memory used by the vGPU", vgpuLabels...),
+		licensed:              newDeviceDesc(cfg, "vgpu_licensed", "1 if the guest driver of the vGPU is licensed", vgpuLabels...),
+		utilizationSM:         newDeviceDesc(cfg, "vgpu_utilization_sm", "SM utilization of the vGPU", vgpuLabels...),
+		utilizationMemory:     newDeviceDesc(cfg, "vgpu_utilization_memory", "Memory utilization of the vGPU", vgpuLabels...),
+		utilizationEncoder:    newDeviceDesc(cfg, "vgpu_utilization_encoder", "Encoder utilization of the vGPU", vgpuLabels...),
+		utilizationDecoder:    newDeviceDesc(cfg, "vgpu_utilization_decoder", "Decoder utilization of the vGPU", vgpuLabels...),
+		encoderSessions:       newDeviceDesc(cfg, "vgpu_encoder_sessions", "Active encoder sessions of the vGPU", vgpuLabels...),
+		encoderAverageFPS:     newDeviceDesc(cfg, "vgpu_encoder_average_fps", "Average frame rate of the encoder sessions of the vGPU", vgpuLabels...),
+		encoderAverageLatency: newDeviceDesc(cfg, "vgpu_encoder_average_latency_seconds", "Average latency of the encoder sessions of the vGPU", vgpuLabels...),
+		fbcSessions:           newDeviceDesc(cfg, "vgpu_fbc_sessions", "Active frame buffer capture sessions of the vGPU", vgpuLabels...),
+		fbcAverageFPS:         newDeviceDesc(cfg, "vgpu_fbc_average_fps", "Average frame rate of the frame buffer capture sessions of the vGPU", vgpuLabels...),
+		fbcAverageLatency:     newDeviceDesc(cfg, "vgpu_fbc_average_latency_seconds", "Average latency of the frame buffer capture sessions of the vGPU", vgpuLabels...),
+		guestInfo:             newDeviceDesc(cfg, "vgpu_guest_info", "A metric with a constant '1' value labeled by the driver version and vGPU type of a guest", "driver_version", "vgpu_type"),
+		licenseLicensed:       newDeviceDesc(cfg, "vgpu_license_licensed", "1 if the licensable feature is licensed in the guest", licenseLabels...),
+		licenseState:          newDeviceDesc(cfg, "vgpu_license_state", "License state of the vGPU", withLabels(licenseLabels, "state")...),
+		licenseEnabled:        newDeviceDesc(cfg, "vgpu_license_enabled", "1 if the licensable feature is enabled in the guest", licenseLabels...),
+		licenseExpiry:         newDeviceDesc(cfg, "vgpu_license_expiry_timestamp_seconds", "Time the license expires", licenseLabels...),
+		licenseDaysRemaining:  newDeviceDesc(cfg, "vgpu_license_days_remaining", "Days until the license expires", licenseLabels...),
+	}
+}
+
+func (c *vgpuCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	mode, ret := device.GetVirtualizationMode()
+	if !d.check("GetVirtualizationMode", ret, "virtualization_mode") {
+		return nil
+	}
+	d.VirtualizationMode = virtualizationModes[mode]
+	switch mode {
+	case nvml.GPU_VIRTUALIZATION_MODE_HOST_VGPU:
+		c.updateHost(device, d)
+	case nvml.GPU_VIRTUALIZATION_MODE_VGPU:
+		c.updateGuest(lib, device, d)
+	}
+	return nil
+}
+
+// updateHost reads the vGPU instances running on a host
+func (c *vgpuCollector) updateHost(device nvml.Device, d *Device) {
+	instances, ret := device.GetActiveVgpus()
+	if !d.check("GetActiveVgpus", ret) {
+		return
+	}
+	samples := vgpuUtilization(device, d)
+	matched := false
+	for _, instance := range instances {
+		v := &Vgpu{
+			FbUsage: -1, Licensed: -1,
+			UtilizationSM: -1, UtilizationMemory: -1, UtilizationEncoder: -1, UtilizationDecoder: -1,
+			EncoderSessions: -1, EncoderAverageFPS: -1, EncoderAverageLatency: -1,
+			FBCSessions: -1, FBCAverageFPS: -1, FBCAverageLatency: -1,
+		}
+		uuid, ret := instance.GetUUID()
+		if d.check("VgpuInstanceGetUUID", ret) {
+			v.UUID = uuid
+		}
+		vmID, _, ret := instance.GetVmID()
+		if d.check("VgpuInstanceGetVmID", ret) {
+			v.VMID = vmID
+		}
+		vgpuType, ret := instance.GetType()
+		if d.check("VgpuInstanceGetType", ret) {
+			name, ret := vgpuType.GetName()
+			if d.check("VgpuTypeGetName", ret) {
+				v.Type = name
+			}
+		}
+		fbUsage, ret := instance.GetFbUsage()
+		if d.check("VgpuInstanceGetFbUsage", ret) {
+			v.FbUsage = float64(fbUsage)
+		}
+		licensed, ret := instance.GetLicenseStatus()
+		if d.check("VgpuInstanceGetLicenseStatus", ret) {
+			v.Licensed = boolFloat(licensed != 0)
+		}
+		license, ret := instance.GetLicenseInfo()
+		if d.check("VgpuInstanceGetLicenseInfo", ret) {
+			l := &VgpuLicense{
+				VgpuUUID: v.UUID,
+				VMID:     v.VMID,
+				Type:     v.Type,
+				State:    gridLicenseStates[license.CurrentState],
+				Licensed: -1,
+				Enabled:  -1,
+			}
+			l.setExpiry(nvml.GridLicenseExpiry(license.LicenseExpiry))
+			d.VgpuLicenses = append(d.VgpuLicenses, l)
+		}
+		if sample, ok := samples[vgpuHandle(instance)]; ok {
+			matched = true
+			v.UtilizationSM = sample.value(sample.SmUtil)
+			v.UtilizationMemory = sample.value(sample.MemUtil)
+			v.UtilizationEncoder = sample.value(sample.EncUtil)
+			v.UtilizationDecoder = sample.value(sample.DecUtil)
+		}
+		// Latencies are reported in microseconds
+		sessions, fps, latency, ret := instance.GetEncoderStats()
+		if d.check("VgpuInstanceGetEncoderStats", ret) {
+			v.EncoderSessions = float64(sessions)
+			v.EncoderAverageFPS = float64(fps)
+			v.EncoderAverageLatency = float64(latency) / 1e6
+		}
+		fbc, ret := instance.GetFBCStats()
+		if d.check("VgpuInstanceGetFBCStats", ret) {
+			v.FBCSessions = float64(fbc.SessionsCount)
+			v.FBCAverageFPS = float64(fbc.AverageFPS)
+			v.FBCAverageLatency = float64(fbc.AverageLatency) / 1e6
+		}
+		d.Vgpus = append(d.Vgpus, v)
+	}
+	if len(samples) > 0 && !matched {
+		log.Warnf("Couldn't match the vGPU utilization samples of GPU %s to any vGPU instance", d.Index)
+	}
+}
+
+// updateGuest reads the driver version and licensing state inside a guest,
+// where the device name is the vGPU type
+func (c *vgpuCollector) updateGuest(lib nvml.Interface, device nvml.Device, d *Device) {
+	d.VgpuGuest = true
+	version, ret := lib.SystemGetDriverVersion()
+	if d.check("SystemGetDriverVersion", ret) {
+		d.VgpuGuestDriverVersion = version
+	}
+	features, ret := device.GetGridLicensableFeatures()
+	if !d.check("GetGridLicensableFeatures", ret) || features.IsGridLicenseSupported == 0 {
+		return
+	}
+	count := min(int(features.LicensableFeaturesCount), len(features.GridLicensableFeatures))
+	for _, f := range features.GridLicensableFeatures[:count] {
+		feature, ok := gridLicenseFeatures[f.FeatureCode]
+		if !ok {
+			feature = strconv.FormatUint(uint64(f.FeatureCode), 10)
+		}
+		l := &VgpuLicense{
+			Type:     d.Name,
+			Feature:  feature,
+			Licensed: boolFloat(f.FeatureState != 0),
+			Enabled:  boolFloat(f.FeatureEnabled != 0),
+		}
+		l.setExpiry(f.LicenseExpiry)
+		d.VgpuLicenses = append(d.VgpuLicenses, l)
+	}
+}
+
+// setExpiry sets the expiry of l if it has a valid one, NVML reports it in UTC
+func (l *VgpuLicense) setExpiry(e nvml.GridLicenseExpiry) {
+	l.DaysRemaining = -1
+	if e.Status != nvml.GRID_LICENSE_EXPIRY_VALID {
+		return
+	}
+	l.Expiry = time.Date(int(e.Year), time.Month(e.Month), int(e.Day), int(e.Hour), int(e.Min), int(e.Sec), 0, time.UTC)
+	l.DaysRemaining = max(time.Until(l.Expiry).Hours()/24, 0)
+}
+
+// vgpuSample is the latest utilization sample of a vGPU instance
+type vgpuSample struct {
+	nvml.VgpuInstanceUtilizationSample
+	valueType nvml.ValueType
+}
+
+// value decodes a utilization value of the sample, -1 if its type can't be
+// decoded
+func (s vgpuSample) value(v [8]byte) float64 {
+	value, ok := decodeValue(s.valueType, v)
+	if !ok {
+		return -1
+	}
+	return value
+}
+
+// vgpuUtilization returns the latest utilization sample of every vGPU
+// instance on device, keyed by vgpuHandle
+func vgpuUtilization(device nvml.Device, d *Device) map[string]vgpuSample {
+	samples := make(map[string]vgpuSample)
+	valueType, utilization, ret := device.GetVgpuUtilization(0)
+	// NOT_FOUND only means there were no samples yet
+	if ret == nvml.ERROR_NOT_FOUND || !d.check("GetVgpuUtilization", ret) {
+		return samples
+	}
+	for _, sample := range utilization {
+		key := vgpuHandle(sample.VgpuInstance)
+		if latest, ok := samples[key]; !ok || sample.TimeStamp > latest.TimeStamp {
+			samples[key] = vgpuSample{sample, valueType}
+		}
+	}
+	return samples
+}
+
+// vgpuHandle returns the key matching a vGPU instance with its utilization
+// samples. go-nvml has no accessor for the ID of an instance, but the NVML
+// handle of an instance is its ID, so the handle and the ID of its samples
+// print the same.
+func vgpuHandle(handle any) string {
+	return fmt.Sprint(handle)
+}
+
+func (c *vgpuCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.virtualizationMode
+	ch <- c.info
+	ch <- c.fbUsage
+	ch <- c.licensed
+	ch <- c.utilizationSM
+	ch <- c.utilizationMemory
+	ch <- c.utilizationEncoder
+	ch <- c.utilizationDecoder
+	ch <- c.encoderSessions
+	ch <- c.encoderAverageFPS
+	ch <- c.encoderAverageLatency
+	ch <- c.fbcSessions
+	ch <- c.fbcAverageFPS
+	ch <- c.fbcAverageLatency
+	ch <- c.guestInfo
+	ch <- c.licenseLicensed
+	ch <- c.licenseState
+	ch <- c.licenseEnabled
+	ch <- c.licenseExpiry
+	ch <- c.licenseDaysRemaining
+}
+
+func (c *vgpuCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	if d.Supported["virtualization_mode"] && d.VirtualizationMode != "" {
+		ch <- prometheus.MustNewConstMetric(c.virtualizationMode, prometheus.GaugeValue, 1, d.labelValues(c.labels, d.VirtualizationMode)...)
+	}
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		if value >= 0 {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+		}
+	}
+	for _, v := range d.Vgpus {
+		labels := d.labelValues(c.labels, v.UUID, v.VMID, v.Type)
+		gauge(c.info, 1, labels...)
+		gauge(c.fbUsage, v.FbUsage, labels...)
+		gauge(c.licensed, v.Licensed, labels...)
+		gauge(c.utilizationSM, v.UtilizationSM, labels...)
+		gauge(c.utilizationMemory, v.UtilizationMemory, labels...)
+		gauge(c.utilizationEncoder, v.UtilizationEncoder, labels...)
+		gauge(c.utilizationDecoder, v.UtilizationDecoder, labels...)
+		gauge(c.encoderSessions, v.EncoderSessions, labels...)
+		gauge(c.encoderAverageFPS, v.EncoderAverageFPS, labels...)
+		gauge(c.encoderAverageLatency, v.EncoderAverageLatency, labels...)
+		gauge(c.fbcSessions, v.FBCSessions, labels...)
+		gauge(c.fbcAverageFPS, v.FBCAverageFPS, labels...)
+		gauge(c.fbcAverageLatency, v.FBCAverageLatency, labels...)
+	}
+	if d.VgpuGuest {
+		gauge(c.guestInfo, 1, d.labelValues(c.labels, d.VgpuGuestDriverVersion, d.Name)...)
+	}
+	for _, l := range d.VgpuLicenses {
+		labels := d.labelValues(c.labels, l.VgpuUUID, l.VMID, l.Type, l.Feature)
+		gauge(c.licenseLicensed, l.Licensed, labels...)
+		if l.State != "" {
+			gauge(c.licenseState, 1, withLabels(labels, l.State)...)
+		}
+		gauge(c.licenseEnabled, l.Enabled, labels...)
+		if !l.Expiry.IsZero() {
+			gauge(c.licenseExpiry, float64(l.Expiry.Unix()), labels...)
+		}
+		gauge(c.licenseDaysRemaining, l.DaysRemaining, labels...)
+	}
+}
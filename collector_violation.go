@@ -0,0 +1,63 @@
+package main
+
+import (
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// perfPolicies maps the performance policies with violation counters to
+// label values
+var perfPolicies = []struct {
+	policy nvml.PerfPolicyType
+	name   string
+}{
+	{nvml.PERF_POLICY_POWER, "power"},
+	{nvml.PERF_POLICY_THERMAL, "thermal"},
+	{nvml.PERF_POLICY_SYNC_BOOST, "sync_boost"},
+	{nvml.PERF_POLICY_BOARD_LIMIT, "board_limit"},
+	{nvml.PERF_POLICY_LOW_UTILIZATION, "low_utilization"},
+	{nvml.PERF_POLICY_RELIABILITY, "reliability"},
+}
+
+type violationCollector struct {
+	labels     []string
+	violations *prometheus.Desc
+}
+
+func init() {
+	registerCollector("violation", true, newViolationCollector)
+}
+
+func newViolationCollector(cfg collectorConfig) Collector {
+	return &violationCollector{
+		labels:     cfg.DeviceLabels,
+		violations: newDeviceDesc(cfg, "violation_seconds_total", "Time the device spent with clocks capped by the given policy", "policy"),
+	}
+}
+
+func (c *violationCollector) Update(lib nvml.Interface, device nvml.Device, d *Device) error {
+	d.Violations = make(map[string]float64)
+	for _, p := range perfPolicies {
+		violation, ret := device.GetViolationStatus(p.policy)
+		// Not every policy is tracked on every device
+		if ret == nvml.ERROR_NOT_SUPPORTED {
+			continue
+		}
+		if d.check("GetViolationStatus", ret) {
+			d.Violations[p.name] = time.Duration(violation.ViolationTime).Seconds()
+		}
+	}
+	return nil
+}
+
+func (c *violationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.violations
+}
+
+func (c *violationCollector) Collect(d *Device, ch chan<- prometheus.Metric) {
+	for policy, seconds := range d.Violations {
+		ch <- prometheus.MustNewConstMetric(c.violations, prometheus.CounterValue, seconds, d.labelValues(c.labels, policy)...)
+	}
+}
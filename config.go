@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+)
+
+// metricNameRe matches the names allowed for configured metrics
+var metricNameRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// fileConfig is the configuration file given with --config.file
+type fileConfig struct {
+	Fields []fieldConfig `yaml:"fields"`
+}
+
+// fieldConfig describes an NVML field exported by the fields collector
+type fieldConfig struct {
+	// ID is the field ID, one of the nvml.FI_* constants
+	ID uint32 `yaml:"id"`
+	// Scopes are the scope IDs to read the field for, e.g. NVLink links,
+	// each exported with a scope label. Without scopes the field is read once.
+	Scopes []uint32 `yaml:"scopes"`
+	// Name is the metric name without the nvidia_ prefix, defaults to field_<id>
+	Name string `yaml:"name"`
+	Help string `yaml:"help"`
+	// Scale multiplies the value, e.g. 0.001 to turn mW into W, defaults to 1
+	Scale float64 `yaml:"scale"`
+	// Type is gauge or counter, defaults to gauge
+	Type string `yaml:"type"`
+}
+
+// loadConfig reads and validates the configuration file at path, an empty
+// path returns an empty configuration
+func loadConfig(path string) (*fileConfig, error) {
+	cfg := &fileConfig{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	names := make(map[string]bool)
+	for i := range cfg.Fields {
+		f := &cfg.Fields[i]
+		if f.Name == "" {
+			f.Name = "field_" + strconv.FormatUint(uint64(f.ID), 10)
+		}
+		if !metricNameRe.MatchString(f.Name) {
+			return nil, fmt.Errorf("invalid metric name %q for field %d", f.Name, f.ID)
+		}
+		if names[f.Name] {
+			return nil, fmt.Errorf("metric name %q is used by more than one field", f.Name)
+		}
+		names[f.Name] = true
+		if f.Help == "" {
+			f.Help = fmt.Sprintf("NVML field %d", f.ID)
+		}
+		if f.Scale == 0 {
+			f.Scale = 1
+		}
+		switch f.Type {
+		case "":
+			f.Type = "gauge"
+		case "gauge", "counter":
+		default:
+			return nil, fmt.Errorf("invalid type %q for field %d, expected gauge or counter", f.Type, f.ID)
+		}
+	}
+	return cfg, nil
+}
+
+// checkFieldNames returns an error if a configured field has one of the
+// names of the built-in metrics, which would make registering the exporter fail
+func checkFieldNames(fields []fieldConfig, names []string) error {
+	for _, f := range fields {
+		if slices.Contains(names, prometheus.BuildFQName(namespace, "", f.Name)) {
+			return fmt.Errorf("metric name %q for field %d is already used by the exporter", f.Name, f.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1620 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeReturn is an NVML return code which can be written by name in a fixture,
+// with or without the ERROR_ prefix
+type fakeReturn nvml.Return
+
+func (r *fakeReturn) UnmarshalText(text []byte) error {
+	name := strings.ToUpper(string(text))
+	if ret, ok := returnCodes[name]; ok {
+		*r = fakeReturn(ret)
+		return nil
+	}
+	if ret, ok := returnCodes["ERROR_"+name]; ok {
+		*r = fakeReturn(ret)
+		return nil
+	}
+	return fmt.Errorf("unknown NVML return code %q", text)
+}
+
+// fakeReturns overrides the return code of NVML calls, keyed by method name
+// (e.g. GetFanSpeed or SystemGetDriverVersion)
+type fakeReturns map[string]fakeReturn
+
+// fakeFixture describes the system simulated by the fake backend
+type fakeFixture struct {
+	DriverVersion string              `yaml:"driver_version"`
+	Returns       fakeReturns         `yaml:"returns"`
+	Devices       []*fakeDeviceConfig `yaml:"devices"`
+}
+
+type fakeDeviceConfig struct {
+	UUID              string `yaml:"uuid"`
+	Name              string `yaml:"name"`
+	Minor             int    `yaml:"minor"`
+	PciBusID          string `yaml:"pci_bus_id"`
+	Temperature       uint32 `yaml:"temperature"`
+	PowerUsage        uint32 `yaml:"power_usage"`
+	PowerLimit        uint32 `yaml:"power_limit"`
+	FanSpeed          uint32 `yaml:"fan_speed"`
+	MemoryTotal       uint64 `yaml:"memory_total"`
+	MemoryUsed        uint64 `yaml:"memory_used"`
+	UtilizationGPU    uint32 `yaml:"utilization_gpu"`
+	UtilizationMemory uint32 `yaml:"utilization_memory"`
+	ClockGraphics     uint32 `yaml:"clock_graphics"`
+	ClockMemory       uint32 `yaml:"clock_memory"`
+	PcieTx            uint32 `yaml:"pcie_tx"`
+	PcieRx            uint32 `yaml:"pcie_rx"`
+	UtilizationEnc    uint32 `yaml:"utilization_encoder"`
+	UtilizationDec    uint32 `yaml:"utilization_decoder"`
+	// ClockEventReasons and SupportedClockEventReasons are raw
+	// nvml.ClocksEventReason* bitmasks
+	ClockEventReasons          uint64         `yaml:"clock_event_reasons"`
+	SupportedClockEventReasons uint64         `yaml:"supported_clock_event_reasons"`
+	EccMode                    bool           `yaml:"ecc_mode"`
+	EccModePending             bool           `yaml:"ecc_mode_pending"`
+	EccErrors                  []fakeEccError `yaml:"ecc_errors"`
+	SramThresholdExceeded      bool           `yaml:"sram_threshold_exceeded"`
+	// RetiredPagesSBE and RetiredPagesDBE are the number of pages retired
+	// because of single and double bit errors
+	RetiredPagesSBE         int                   `yaml:"retired_pages_sbe"`
+	RetiredPagesDBE         int                   `yaml:"retired_pages_dbe"`
+	RetiredPagesPending     bool                  `yaml:"retired_pages_pending"`
+	RemappedRowsCorrected   int                   `yaml:"remapped_rows_corrected"`
+	RemappedRowsUncorrected int                   `yaml:"remapped_rows_uncorrected"`
+	RemappedRowsPending     bool                  `yaml:"remapped_rows_pending"`
+	RemappedRowsFailure     bool                  `yaml:"remapped_rows_failure"`
+	RowRemapperHistogram    fakeRemapperHistogram `yaml:"row_remapper_histogram"`
+	// NvLinks are the links of the device, without any every NVLink call
+	// returns NOT_SUPPORTED
+	NvLinks []fakeNvLink `yaml:"nvlinks"`
+	// Energy is the total energy consumption in mJ
+	Energy uint64 `yaml:"energy"`
+	// FieldValues are returned by GetFieldValues keyed by field ID, e.g. 186
+	// for FI_DEV_POWER_INSTANT, the same value is returned for every scope and
+	// other fields return NOT_SUPPORTED
+	FieldValues       map[uint32]float64 `yaml:"field_values"`
+	PcieLinkGen       int                `yaml:"pcie_link_gen"`
+	PcieLinkGenMax    int                `yaml:"pcie_link_gen_max"`
+	PcieLinkGenGpuMax int                `yaml:"pcie_link_gen_gpu_max"`
+	PcieLinkWidth     int                `yaml:"pcie_link_width"`
+	PcieLinkWidthMax  int                `yaml:"pcie_link_width_max"`
+	// PcieSpeed and PcieLinkMaxSpeed are in MBPS
+	PcieSpeed        int     `yaml:"pcie_speed"`
+	PcieLinkMaxSpeed float64 `yaml:"pcie_link_max_speed"`
+	PcieReplays      int     `yaml:"pcie_replays"`
+	// TemperatureThresholds are keyed by the threshold label value, e.g.
+	// slowdown, thresholds not listed return NOT_SUPPORTED
+	TemperatureThresholds map[string]uint32   `yaml:"temperature_thresholds"`
+	ThermalSensors        []fakeThermalSensor `yaml:"thermal_sensors"`
+	ClockSM               uint32              `yaml:"clock_sm"`
+	ClockVideo            uint32              `yaml:"clock_video"`
+	// The clock envelope maps are keyed by clock label value (graphics, sm,
+	// memory or video), clocks not listed return NOT_SUPPORTED
+	ClocksMax                 map[string]uint32 `yaml:"clocks_max"`
+	ClocksMaxBoost            map[string]uint32 `yaml:"clocks_max_boost"`
+	ClocksApplications        map[string]uint32 `yaml:"clocks_applications"`
+	ClocksDefaultApplications map[string]uint32 `yaml:"clocks_default_applications"`
+	// PstateClocks are the min and max clocks of the current P-state
+	PstateClocks map[string][2]uint32 `yaml:"pstate_clocks"`
+	// Pstate is the current P-state, without one it is unknown
+	Pstate *int `yaml:"pstate"`
+	// DynamicPstates is the utilization by domain (gpu, fb, vid or bus)
+	DynamicPstates map[string]uint32 `yaml:"dynamic_pstates"`
+	// Violations is the time spent capped in seconds by policy label value,
+	// policies not listed return NOT_SUPPORTED
+	Violations map[string]float64 `yaml:"violations"`
+	// Fans are returned by the per-fan calls, FanSpeed is still used for the
+	// single-fan GetFanSpeed
+	Fans        []fakeFan `yaml:"fans"`
+	FanSpeedMin int       `yaml:"fan_speed_min"`
+	FanSpeedMax int       `yaml:"fan_speed_max"`
+	// Power management limits are in mW like power_limit, the default limit
+	// is power_limit when not set
+	PowerManagementLimit uint32 `yaml:"power_management_limit"`
+	PowerDefaultLimit    uint32 `yaml:"power_default_limit"`
+	PowerLimitMin        uint32 `yaml:"power_limit_min"`
+	PowerLimitMax        uint32 `yaml:"power_limit_max"`
+	PowerManagementMode  bool   `yaml:"power_management_mode"`
+	// PowerSource is ac, battery or undersized
+	PowerSource string `yaml:"power_source"`
+	// Mig is the MIG configuration, devices without it don't support MIG
+	Mig *fakeMig `yaml:"mig"`
+	// VirtualizationMode is none (the default), passthrough, vgpu, host_vgpu
+	// or host_vsga
+	VirtualizationMode string `yaml:"virtualization_mode"`
+	// Vgpus are the vGPU instances running on a host_vgpu device, their IDs
+	// must be unique across devices
+	Vgpus []fakeVgpu `yaml:"vgpus"`
+	// GridFeatures are the licensable features of a device in vgpu mode,
+	// at most three
+	GridFeatures []fakeGridFeature `yaml:"grid_licensable_features"`
+	// Gpm are the GPM metric values keyed by metric ID, e.g. 2 for
+	// GPM_METRIC_SM_UTIL, metrics not listed return NOT_SUPPORTED. Devices
+	// without any don't support GPM.
+	Gpm map[uint32]float64 `yaml:"gpm"`
+	// EncoderCapacity is the remaining encoder capacity in percent by codec
+	// (h264, hevc or av1), codecs not listed return NOT_SUPPORTED
+	EncoderCapacity map[string]int `yaml:"encoder_capacity"`
+	// EncoderSessions and FBCSessions are the active sessions, the stats
+	// are averaged over them
+	EncoderSessions []fakeEncoderSession `yaml:"encoder_sessions"`
+	FBCSessions     []fakeEncoderSession `yaml:"fbc_sessions"`
+	Processes       []fakeProcess        `yaml:"processes"`
+	Returns         fakeReturns          `yaml:"returns"`
+	// Delay is added to every call on the device to simulate a hung GPU,
+	// except identity lookups which the driver answers from its cache
+	Delay time.Duration `yaml:"delay"`
+}
+
+// fakeEccError is an ECC error counter, a location only reports errors if at
+// least one counter is configured for it
+type fakeEccError struct {
+	Type     string `yaml:"type"`
+	Counter  string `yaml:"counter"`
+	Location string `yaml:"location"`
+	Count    uint64 `yaml:"count"`
+}
+
+type fakeRemapperHistogram struct {
+	Max     uint32 `yaml:"max"`
+	High    uint32 `yaml:"high"`
+	Partial uint32 `yaml:"partial"`
+	Low     uint32 `yaml:"low"`
+	None    uint32 `yaml:"none"`
+}
+
+type fakeNvLink struct {
+	Up             bool   `yaml:"up"`
+	Version        uint32 `yaml:"version"`
+	RemoteType     string `yaml:"remote_type"`
+	RemotePciBusID string `yaml:"remote_pci_bus_id"`
+	Replay         uint64 `yaml:"replay"`
+	Recovery       uint64 `yaml:"recovery"`
+	CrcFlit        uint64 `yaml:"crc_flit"`
+	CrcData        uint64 `yaml:"crc_data"`
+	RxBytes        uint64 `yaml:"rx_bytes"`
+	TxBytes        uint64 `yaml:"tx_bytes"`
+}
+
+type fakeThermalSensor struct {
+	Target      string `yaml:"target"`
+	Temperature int32  `yaml:"temperature"`
+}
+
+type fakeFan struct {
+	Speed  uint32 `yaml:"speed"`
+	Target int    `yaml:"target"`
+	// Policy is auto or manual
+	Policy string `yaml:"policy"`
+}
+
+// fakeMig describes the MIG mode of a device and its MIG devices
+type fakeMig struct {
+	Enabled    bool `yaml:"enabled"`
+	Pending    bool `yaml:"pending"`
+	MaxDevices int  `yaml:"max_devices"`
+	// The profiles are keyed by profile ID, e.g. 2 for
+	// GPU_INSTANCE_PROFILE_3_SLICE, profiles not listed return NOT_SUPPORTED
+	GpuInstanceProfiles     map[int]fakeMigProfile `yaml:"gpu_instance_profiles"`
+	ComputeInstanceProfiles map[int]fakeMigProfile `yaml:"compute_instance_profiles"`
+	// Devices are returned by GetMigDeviceHandleByIndex in order, the
+	// remaining indices up to MaxDevices return NOT_FOUND
+	Devices []fakeMigDevice `yaml:"devices"`
+}
+
+type fakeMigProfile struct {
+	Slices   uint32 `yaml:"slices"`
+	MemoryMB uint64 `yaml:"memory_mb"`
+}
+
+type fakeMigDevice struct {
+	UUID                   string           `yaml:"uuid"`
+	GpuInstance            uint32           `yaml:"gpu_instance"`
+	GpuInstanceProfile     int              `yaml:"gpu_instance_profile"`
+	ComputeInstance        uint32           `yaml:"compute_instance"`
+	ComputeInstanceProfile int              `yaml:"compute_instance_profile"`
+	MemoryTotal            uint64           `yaml:"memory_total"`
+	MemoryUsed             uint64           `yaml:"memory_used"`
+	Processes              []fakeMigProcess `yaml:"processes"`
+	// Gpm are the GPM metric values of the GPU instance, the first device
+	// of a GPU instance with any is used
+	Gpm map[uint32]float64 `yaml:"gpm"`
+}
+
+type fakeMigProcess struct {
+	PID        uint32 `yaml:"pid"`
+	UsedMemory uint64 `yaml:"used_memory"`
+}
+
+type fakeVgpu struct {
+	ID       uint32 `yaml:"id"`
+	UUID     string `yaml:"uuid"`
+	VMID     string `yaml:"vm_id"`
+	Type     string `yaml:"type"`
+	FbUsage  uint64 `yaml:"fb_usage"`
+	Licensed bool   `yaml:"licensed"`
+	SMUtil   uint32 `yaml:"sm_util"`
+	MemUtil  uint32 `yaml:"mem_util"`
+	EncUtil  uint32 `yaml:"enc_util"`
+	DecUtil  uint32 `yaml:"dec_util"`
+	// Latencies are in microseconds
+	EncoderSessions uint32 `yaml:"encoder_sessions"`
+	EncoderFPS      uint32 `yaml:"encoder_fps"`
+	EncoderLatency  uint32 `yaml:"encoder_latency"`
+	FBCSessions     uint32 `yaml:"fbc_sessions"`
+	FBCFPS          uint32 `yaml:"fbc_fps"`
+	FBCLatency      uint32 `yaml:"fbc_latency"`
+	// LicenseState is one of the vgpu_license_state label values, licensed by
+	// default when licensed is set
+	LicenseState  string          `yaml:"license_state"`
+	LicenseExpiry fakeLicenseTime `yaml:"license_expiry"`
+}
+
+// fakeGridFeature is a licensable feature seen from inside a guest
+type fakeGridFeature struct {
+	// Feature is one of the feature label values, e.g. vgpu or compute, or
+	// a numeric feature code
+	Feature  string          `yaml:"feature"`
+	Licensed bool            `yaml:"licensed"`
+	Enabled  bool            `yaml:"enabled"`
+	Expiry   fakeLicenseTime `yaml:"expiry"`
+}
+
+// fakeLicenseTime is a license expiry, either a time, permanent or empty
+// when not available
+type fakeLicenseTime struct {
+	Permanent bool
+	Time      time.Time
+}
+
+func (t *fakeLicenseTime) UnmarshalText(text []byte) error {
+	if string(text) == "permanent" {
+		t.Permanent = true
+		return nil
+	}
+	return t.Time.UnmarshalText(text)
+}
+
+// expiry returns t in the format used by NVML
+func (t fakeLicenseTime) expiry() nvml.GridLicenseExpiry {
+	switch {
+	case t.Permanent:
+		return nvml.GridLicenseExpiry{Status: nvml.GRID_LICENSE_EXPIRY_PERMANENT}
+	case t.Time.IsZero():
+		return nvml.GridLicenseExpiry{Status: nvml.GRID_LICENSE_EXPIRY_NOT_AVAILABLE}
+	}
+	u := t.Time.UTC()
+	return nvml.GridLicenseExpiry{
+		Year:   uint32(u.Year()),
+		Month:  uint16(u.Month()),
+		Day:    uint16(u.Day()),
+		Hour:   uint16(u.Hour()),
+		Min:    uint16(u.Minute()),
+		Sec:    uint16(u.Second()),
+		Status: nvml.GRID_LICENSE_EXPIRY_VALID,
+	}
+}
+
+// fakeEncoderSession is an encoder or FBC session, type is the codec of an
+// encoder session and the capture type (tosys, cuda, vid or hwenc) of an FBC
+// session. Latency is in microseconds.
+type fakeEncoderSession struct {
+	ID      uint32 `yaml:"id"`
+	PID     uint32 `yaml:"pid"`
+	Type    string `yaml:"type"`
+	Width   uint32 `yaml:"width"`
+	Height  uint32 `yaml:"height"`
+	FPS     uint32 `yaml:"fps"`
+	Latency uint32 `yaml:"latency"`
+}
+
+// fakeProcess is a process utilization sample, a PID may have several
+// samples with different timestamps
+type fakeProcess struct {
+	PID       uint32 `yaml:"pid"`
+	Timestamp uint64 `yaml:"timestamp"`
+	Name      string `yaml:"name"`
+	SMUtil    uint32 `yaml:"sm_util"`
+	MemUtil   uint32 `yaml:"mem_util"`
+	EncUtil   uint32 `yaml:"enc_util"`
+	DecUtil   uint32 `yaml:"dec_util"`
+}
+
+//go:generate go run gen_unsupported.go
+
+// fakeNvml implements nvml.Interface on top of a fixture file.
+// Only the calls used by the exporter are implemented, anything else
+// returns ERROR_NOT_SUPPORTED through the embedded stubs.
+type fakeNvml struct {
+	unsupportedInterface
+	path string
+	// mu serializes loads, state is swapped atomically so device calls
+	// still running from an earlier collection keep their own snapshot
+	mu    sync.Mutex
+	state atomic.Pointer[fakeState]
+}
+
+// fakeState is a loaded fixture, it isn't modified after the load
+type fakeState struct {
+	modTime time.Time
+	fixture *fakeFixture
+	devices []*fakeDevice
+}
+
+// fakeDevice implements nvml.Device for a single fixture device
+type fakeDevice struct {
+	unsupportedDevice
+	state *fakeState
+	cfg   *fakeDeviceConfig
+}
+
+// newFakeNvml loads a YAML or JSON fixture from path
+func newFakeNvml(path string) (*fakeNvml, error) {
+	f := &fakeNvml{path: path}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// load (re)reads the fixture if it changed on disk. This happens on Init and
+// at the start of every collection, so editing the fixture while the exporter
+// is running simulates driver errors and reloads.
+func (f *fakeNvml) load() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if s := f.state.Load(); s != nil && info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var fixture fakeFixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		return fmt.Errorf("failed to parse fixture %s: %w", f.path, err)
+	}
+	state := &fakeState{modTime: info.ModTime(), fixture: &fixture}
+	vgpus := make(map[fakeVgpuInstance]*fakeVgpu)
+	for _, cfg := range fixture.Devices {
+		state.devices = append(state.devices, &fakeDevice{state: state, cfg: cfg})
+		for i := range cfg.Vgpus {
+			vgpus[fakeVgpuInstance(cfg.Vgpus[i].ID)] = &cfg.Vgpus[i]
+		}
+	}
+	f.state.Store(state)
+	fakeVgpus.Store(&vgpus)
+	return nil
+}
+
+// ret returns the configured return code for a library-level call
+func (f *fakeNvml) ret(call string) nvml.Return {
+	return f.state.Load().ret(call)
+}
+
+func (s *fakeState) ret(call string) nvml.Return {
+	if r, ok := s.fixture.Returns[call]; ok {
+		return nvml.Return(r)
+	}
+	return nvml.SUCCESS
+}
+
+// ret returns the configured return code for a device call, falling back
+// to the library-wide override for the same call
+func (d *fakeDevice) ret(call string) nvml.Return {
+	switch call {
+	case "DeviceGetHandleByIndex", "GetUUID", "GetName", "GetMinorNumber", "GetPciInfo":
+	default:
+		time.Sleep(d.cfg.Delay)
+	}
+	if r, ok := d.cfg.Returns[call]; ok {
+		return nvml.Return(r)
+	}
+	return d.state.ret(call)
+}
+
+func (f *fakeNvml) Init() nvml.Return {
+	if err := f.load(); err != nil {
+		log.Errorf("Fake backend: %s", err)
+		return nvml.ERROR_UNKNOWN
+	}
+	return f.ret("Init")
+}
+
+func (f *fakeNvml) Shutdown() nvml.Return {
+	return f.ret("Shutdown")
+}
+
+func (f *fakeNvml) SystemGetDriverVersion() (string, nvml.Return) {
+	return f.state.Load().fixture.DriverVersion, f.ret("SystemGetDriverVersion")
+}
+
+func (f *fakeNvml) DeviceGetCount() (int, nvml.Return) {
+	if err := f.load(); err != nil {
+		log.Errorf("Fake backend: %s", err)
+	}
+	s := f.state.Load()
+	return len(s.devices), s.ret("DeviceGetCount")
+}
+
+func (f *fakeNvml) DeviceGetHandleByIndex(index int) (nvml.Device, nvml.Return) {
+	s := f.state.Load()
+	if index < 0 || index >= len(s.devices) {
+		return nil, nvml.ERROR_INVALID_ARGUMENT
+	}
+	d := s.devices[index]
+	return d, d.ret("DeviceGetHandleByIndex")
+}
+
+func (f *fakeNvml) SystemGetProcessName(pid int) (string, nvml.Return) {
+	if ret := f.ret("SystemGetProcessName"); ret != nvml.SUCCESS {
+		return "", ret
+	}
+	for _, d := range f.state.Load().devices {
+		for _, p := range d.cfg.Processes {
+			if int(p.PID) == pid && p.Name != "" {
+				return p.Name, nvml.SUCCESS
+			}
+		}
+	}
+	return "", nvml.ERROR_NOT_FOUND
+}
+
+func (d *fakeDevice) GetUUID() (string, nvml.Return) {
+	return d.cfg.UUID, d.ret("GetUUID")
+}
+
+func (d *fakeDevice) GetName() (string, nvml.Return) {
+	return d.cfg.Name, d.ret("GetName")
+}
+
+func (d *fakeDevice) GetMinorNumber() (int, nvml.Return) {
+	return d.cfg.Minor, d.ret("GetMinorNumber")
+}
+
+func (d *fakeDevice) GetPciInfo() (nvml.PciInfo, nvml.Return) {
+	return fakePciInfo(d.cfg.PciBusID), d.ret("GetPciInfo")
+}
+
+// fakePciInfo returns PCI info holding the given bus ID
+func fakePciInfo(busID string) nvml.PciInfo {
+	var info nvml.PciInfo
+	for i := 0; i < len(busID) && i < len(info.BusId)-1; i++ {
+		info.BusId[i] = int8(busID[i])
+	}
+	return info
+}
+
+func (d *fakeDevice) GetTemperature(sensor nvml.TemperatureSensors) (uint32, nvml.Return) {
+	return d.cfg.Temperature, d.ret("GetTemperature")
+}
+
+func (d *fakeDevice) GetPowerUsage() (uint32, nvml.Return) {
+	return d.cfg.PowerUsage, d.ret("GetPowerUsage")
+}
+
+func (d *fakeDevice) GetEnforcedPowerLimit() (uint32, nvml.Return) {
+	return d.cfg.PowerLimit, d.ret("GetEnforcedPowerLimit")
+}
+
+func (d *fakeDevice) GetFanSpeed() (uint32, nvml.Return) {
+	return d.cfg.FanSpeed, d.ret("GetFanSpeed")
+}
+
+func (d *fakeDevice) GetMemoryInfo() (nvml.Memory, nvml.Return) {
+	return nvml.Memory{
+		Total: d.cfg.MemoryTotal,
+		Used:  d.cfg.MemoryUsed,
+		Free:  d.cfg.MemoryTotal - d.cfg.MemoryUsed,
+	}, d.ret("GetMemoryInfo")
+}
+
+func (d *fakeDevice) GetUtilizationRates() (nvml.Utilization, nvml.Return) {
+	return nvml.Utilization{
+		Gpu:    d.cfg.UtilizationGPU,
+		Memory: d.cfg.UtilizationMemory,
+	}, d.ret("GetUtilizationRates")
+}
+
+func (d *fakeDevice) GetClock(clockType nvml.ClockType, clockId nvml.ClockId) (uint32, nvml.Return) {
+	if ret := d.ret("GetClock"); ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	switch clockType {
+	case nvml.CLOCK_GRAPHICS:
+		return d.cfg.ClockGraphics, nvml.SUCCESS
+	case nvml.CLOCK_MEM:
+		return d.cfg.ClockMemory, nvml.SUCCESS
+	case nvml.CLOCK_SM:
+		return d.cfg.ClockSM, nvml.SUCCESS
+	case nvml.CLOCK_VIDEO:
+		return d.cfg.ClockVideo, nvml.SUCCESS
+	}
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (d *fakeDevice) GetPcieThroughput(counter nvml.PcieUtilCounter) (uint32, nvml.Return) {
+	if ret := d.ret("GetPcieThroughput"); ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	if counter == nvml.PCIE_UTIL_TX_BYTES {
+		return d.cfg.PcieTx, nvml.SUCCESS
+	}
+	return d.cfg.PcieRx, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetDecoderUtilization() (uint32, uint32, nvml.Return) {
+	return d.cfg.UtilizationDec, 1000000, d.ret("GetDecoderUtilization")
+}
+
+func (d *fakeDevice) GetEncoderUtilization() (uint32, uint32, nvml.Return) {
+	return d.cfg.UtilizationEnc, 1000000, d.ret("GetEncoderUtilization")
+}
+
+func (d *fakeDevice) GetProcessUtilization(lastSeenTimestamp uint64) ([]nvml.ProcessUtilizationSample, nvml.Return) {
+	if ret := d.ret("GetProcessUtilization"); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	// Like NVML, processes aren't sampled in MIG mode
+	if d.cfg.Mig != nil && d.cfg.Mig.Enabled {
+		return nil, nvml.ERROR_NOT_SUPPORTED
+	}
+	var samples []nvml.ProcessUtilizationSample
+	for _, p := range d.cfg.Processes {
+		samples = append(samples, nvml.ProcessUtilizationSample{
+			Pid:       p.PID,
+			TimeStamp: p.Timestamp,
+			SmUtil:    p.SMUtil,
+			MemUtil:   p.MemUtil,
+			EncUtil:   p.EncUtil,
+			DecUtil:   p.DecUtil,
+		})
+	}
+	return samples, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetCurrentClocksEventReasons() (uint64, nvml.Return) {
+	return d.cfg.ClockEventReasons, d.ret("GetCurrentClocksEventReasons")
+}
+
+func (d *fakeDevice) GetSupportedClocksEventReasons() (uint64, nvml.Return) {
+	return d.cfg.SupportedClockEventReasons, d.ret("GetSupportedClocksEventReasons")
+}
+
+func fakeEnableState(enabled bool) nvml.EnableState {
+	if enabled {
+		return nvml.FEATURE_ENABLED
+	}
+	return nvml.FEATURE_DISABLED
+}
+
+func (d *fakeDevice) GetEccMode() (nvml.EnableState, nvml.EnableState, nvml.Return) {
+	return fakeEnableState(d.cfg.EccMode), fakeEnableState(d.cfg.EccModePending), d.ret("GetEccMode")
+}
+
+// eccErrors sums the configured counters matching the given label values,
+// an empty location matches all of them
+func (d *fakeDevice) eccErrors(errorType nvml.MemoryErrorType, counterType nvml.EccCounterType, location string) (uint64, bool) {
+	var count uint64
+	var found bool
+	for _, e := range d.cfg.EccErrors {
+		if location != "" && e.Location != location {
+			continue
+		}
+		found = true
+		if e.Type == eccErrorTypes[errorType].name && e.Counter == eccCounterTypes[counterType].name {
+			count += e.Count
+		}
+	}
+	return count, found
+}
+
+func (d *fakeDevice) GetTotalEccErrors(errorType nvml.MemoryErrorType, counterType nvml.EccCounterType) (uint64, nvml.Return) {
+	count, _ := d.eccErrors(errorType, counterType, "")
+	return count, d.ret("GetTotalEccErrors")
+}
+
+func (d *fakeDevice) GetMemoryErrorCounter(errorType nvml.MemoryErrorType, counterType nvml.EccCounterType, location nvml.MemoryLocation) (uint64, nvml.Return) {
+	if ret := d.ret("GetMemoryErrorCounter"); ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	for _, l := range eccLocations {
+		if l.location != location {
+			continue
+		}
+		if count, ok := d.eccErrors(errorType, counterType, l.name); ok {
+			return count, nvml.SUCCESS
+		}
+	}
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (d *fakeDevice) GetSramEccErrorStatus() (nvml.EccSramErrorStatus, nvml.Return) {
+	if ret := d.ret("GetSramEccErrorStatus"); ret != nvml.SUCCESS {
+		return nvml.EccSramErrorStatus{}, ret
+	}
+	var status nvml.EccSramErrorStatus
+	var found bool
+	status.VolatileCor, found = d.eccErrors(nvml.MEMORY_ERROR_TYPE_CORRECTED, nvml.VOLATILE_ECC, "sram")
+	if !found {
+		return status, nvml.ERROR_NOT_SUPPORTED
+	}
+	status.VolatileUncSecDed, _ = d.eccErrors(nvml.MEMORY_ERROR_TYPE_UNCORRECTED, nvml.VOLATILE_ECC, "sram")
+	status.AggregateCor, _ = d.eccErrors(nvml.MEMORY_ERROR_TYPE_CORRECTED, nvml.AGGREGATE_ECC, "sram")
+	status.AggregateUncSecDed, _ = d.eccErrors(nvml.MEMORY_ERROR_TYPE_UNCORRECTED, nvml.AGGREGATE_ECC, "sram")
+	if d.cfg.SramThresholdExceeded {
+		status.BThresholdExceeded = 1
+	}
+	return status, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetRetiredPages(cause nvml.PageRetirementCause) ([]uint64, nvml.Return) {
+	if ret := d.ret("GetRetiredPages"); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	count := d.cfg.RetiredPagesSBE
+	if cause == nvml.PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR {
+		count = d.cfg.RetiredPagesDBE
+	}
+	addresses := make([]uint64, count)
+	for i := range addresses {
+		addresses[i] = uint64(i) << 12
+	}
+	return addresses, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetRetiredPagesPendingStatus() (nvml.EnableState, nvml.Return) {
+	return fakeEnableState(d.cfg.RetiredPagesPending), d.ret("GetRetiredPagesPendingStatus")
+}
+
+func (d *fakeDevice) GetRemappedRows() (int, int, bool, bool, nvml.Return) {
+	return d.cfg.RemappedRowsCorrected, d.cfg.RemappedRowsUncorrected,
+		d.cfg.RemappedRowsPending, d.cfg.RemappedRowsFailure, d.ret("GetRemappedRows")
+}
+
+func (d *fakeDevice) GetRowRemapperHistogram() (nvml.RowRemapperHistogramValues, nvml.Return) {
+	h := d.cfg.RowRemapperHistogram
+	return nvml.RowRemapperHistogramValues{
+		Max:     h.Max,
+		High:    h.High,
+		Partial: h.Partial,
+		Low:     h.Low,
+		None:    h.None,
+	}, d.ret("GetRowRemapperHistogram")
+}
+
+// nvLink returns the configuration of a link and the return code of call
+func (d *fakeDevice) nvLink(call string, link int) (*fakeNvLink, nvml.Return) {
+	if ret := d.ret(call); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	if len(d.cfg.NvLinks) == 0 {
+		return nil, nvml.ERROR_NOT_SUPPORTED
+	}
+	if link < 0 || link >= len(d.cfg.NvLinks) {
+		return nil, nvml.ERROR_INVALID_ARGUMENT
+	}
+	return &d.cfg.NvLinks[link], nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetNvLinkState(link int) (nvml.EnableState, nvml.Return) {
+	l, ret := d.nvLink("GetNvLinkState", link)
+	if ret != nvml.SUCCESS {
+		return nvml.FEATURE_DISABLED, ret
+	}
+	return fakeEnableState(l.Up), nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetNvLinkVersion(link int) (uint32, nvml.Return) {
+	l, ret := d.nvLink("GetNvLinkVersion", link)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	return l.Version, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetNvLinkRemoteDeviceType(link int) (nvml.IntNvLinkDeviceType, nvml.Return) {
+	l, ret := d.nvLink("GetNvLinkRemoteDeviceType", link)
+	if ret != nvml.SUCCESS {
+		return nvml.NVLINK_DEVICE_TYPE_UNKNOWN, ret
+	}
+	for t, name := range nvLinkDeviceTypes {
+		if name == l.RemoteType {
+			return t, nvml.SUCCESS
+		}
+	}
+	return nvml.NVLINK_DEVICE_TYPE_UNKNOWN, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetNvLinkRemotePciInfo(link int) (nvml.PciInfo, nvml.Return) {
+	l, ret := d.nvLink("GetNvLinkRemotePciInfo", link)
+	if ret != nvml.SUCCESS {
+		return nvml.PciInfo{}, ret
+	}
+	return fakePciInfo(l.RemotePciBusID), nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetNvLinkErrorCounter(link int, counter nvml.NvLinkErrorCounter) (uint64, nvml.Return) {
+	l, ret := d.nvLink("GetNvLinkErrorCounter", link)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	switch counter {
+	case nvml.NVLINK_ERROR_DL_REPLAY:
+		return l.Replay, nvml.SUCCESS
+	case nvml.NVLINK_ERROR_DL_RECOVERY:
+		return l.Recovery, nvml.SUCCESS
+	case nvml.NVLINK_ERROR_DL_CRC_FLIT:
+		return l.CrcFlit, nvml.SUCCESS
+	case nvml.NVLINK_ERROR_DL_CRC_DATA:
+		return l.CrcData, nvml.SUCCESS
+	}
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+
+// GetNvLinkUtilizationControl reports counter 0 as counting bytes of all packets
+func (d *fakeDevice) GetNvLinkUtilizationControl(link int, counter int) (nvml.NvLinkUtilizationControl, nvml.Return) {
+	if _, ret := d.nvLink("GetNvLinkUtilizationControl", link); ret != nvml.SUCCESS {
+		return nvml.NvLinkUtilizationControl{}, ret
+	}
+	if counter != 0 {
+		return nvml.NvLinkUtilizationControl{}, nvml.SUCCESS
+	}
+	return nvml.NvLinkUtilizationControl{
+		Units:     uint32(nvml.NVLINK_COUNTER_UNIT_BYTES),
+		Pktfilter: uint32(nvml.NVLINK_COUNTER_PKTFILTER_ALL),
+	}, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetNvLinkUtilizationCounter(link int, counter int) (uint64, uint64, nvml.Return) {
+	l, ret := d.nvLink("GetNvLinkUtilizationCounter", link)
+	if ret != nvml.SUCCESS {
+		return 0, 0, ret
+	}
+	if counter != 0 {
+		return 0, 0, nvml.SUCCESS
+	}
+	return l.RxBytes, l.TxBytes, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetTotalEnergyConsumption() (uint64, nvml.Return) {
+	return d.cfg.Energy, d.ret("GetTotalEnergyConsumption")
+}
+
+func (d *fakeDevice) GetFieldValues(values []nvml.FieldValue) nvml.Return {
+	if ret := d.ret("GetFieldValues"); ret != nvml.SUCCESS {
+		return ret
+	}
+	now := time.Now().UnixMicro()
+	for i := range values {
+		v, ok := d.cfg.FieldValues[values[i].FieldId]
+		if !ok {
+			values[i].NvmlReturn = uint32(nvml.ERROR_NOT_SUPPORTED)
+			continue
+		}
+		values[i].NvmlReturn = uint32(nvml.SUCCESS)
+		values[i].Timestamp = now
+		values[i].ValueType = uint32(nvml.VALUE_TYPE_DOUBLE)
+		binary.NativeEndian.PutUint64(values[i].Value[:], math.Float64bits(v))
+	}
+	return nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetCurrPcieLinkGeneration() (int, nvml.Return) {
+	return d.cfg.PcieLinkGen, d.ret("GetCurrPcieLinkGeneration")
+}
+
+func (d *fakeDevice) GetMaxPcieLinkGeneration() (int, nvml.Return) {
+	return d.cfg.PcieLinkGenMax, d.ret("GetMaxPcieLinkGeneration")
+}
+
+func (d *fakeDevice) GetGpuMaxPcieLinkGeneration() (int, nvml.Return) {
+	return d.cfg.PcieLinkGenGpuMax, d.ret("GetGpuMaxPcieLinkGeneration")
+}
+
+func (d *fakeDevice) GetCurrPcieLinkWidth() (int, nvml.Return) {
+	return d.cfg.PcieLinkWidth, d.ret("GetCurrPcieLinkWidth")
+}
+
+func (d *fakeDevice) GetMaxPcieLinkWidth() (int, nvml.Return) {
+	return d.cfg.PcieLinkWidthMax, d.ret("GetMaxPcieLinkWidth")
+}
+
+func (d *fakeDevice) GetPcieSpeed() (int, nvml.Return) {
+	return d.cfg.PcieSpeed, d.ret("GetPcieSpeed")
+}
+
+func (d *fakeDevice) GetPcieLinkMaxSpeed() (uint32, nvml.Return) {
+	for value, speed := range pcieLinkMaxSpeeds {
+		if speed == d.cfg.PcieLinkMaxSpeed {
+			return value, d.ret("GetPcieLinkMaxSpeed")
+		}
+	}
+	return nvml.PCIE_LINK_MAX_SPEED_INVALID, d.ret("GetPcieLinkMaxSpeed")
+}
+
+func (d *fakeDevice) GetPcieReplayCounter() (int, nvml.Return) {
+	return d.cfg.PcieReplays, d.ret("GetPcieReplayCounter")
+}
+
+func (d *fakeDevice) GetTemperatureThreshold(thresholdType nvml.TemperatureThresholds) (uint32, nvml.Return) {
+	if ret := d.ret("GetTemperatureThreshold"); ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	for _, t := range temperatureThresholds {
+		if threshold, ok := d.cfg.TemperatureThresholds[t.name]; ok && t.threshold == thresholdType {
+			return threshold, nvml.SUCCESS
+		}
+	}
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (d *fakeDevice) GetThermalSettings(sensorIndex uint32) (nvml.GpuThermalSettings, nvml.Return) {
+	var settings nvml.GpuThermalSettings
+	if ret := d.ret("GetThermalSettings"); ret != nvml.SUCCESS {
+		return settings, ret
+	}
+	if len(d.cfg.ThermalSensors) == 0 {
+		return settings, nvml.ERROR_NOT_SUPPORTED
+	}
+	for i, sensor := range d.cfg.ThermalSensors[:min(len(d.cfg.ThermalSensors), len(settings.Sensor))] {
+		target := nvml.THERMAL_TARGET_UNKNOWN
+		for t, name := range thermalTargets {
+			if name == sensor.Target {
+				target = t
+			}
+		}
+		settings.Sensor[i] = nvml.GpuThermalSettingsSensor{
+			CurrentTemp: sensor.Temperature,
+			Target:      int32(target),
+		}
+		settings.Count++
+	}
+	return settings, nvml.SUCCESS
+}
+
+// clock looks up a clock type in one of the clock envelope maps
+func (d *fakeDevice) clock(call string, clocks map[string]uint32, clockType nvml.ClockType) (uint32, nvml.Return) {
+	if ret := d.ret(call); ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	for _, t := range clockTypes {
+		if mhz, ok := clocks[t.name]; ok && t.clockType == clockType {
+			return mhz, nvml.SUCCESS
+		}
+	}
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (d *fakeDevice) GetMaxClockInfo(clockType nvml.ClockType) (uint32, nvml.Return) {
+	return d.clock("GetMaxClockInfo", d.cfg.ClocksMax, clockType)
+}
+
+func (d *fakeDevice) GetMaxCustomerBoostClock(clockType nvml.ClockType) (uint32, nvml.Return) {
+	return d.clock("GetMaxCustomerBoostClock", d.cfg.ClocksMaxBoost, clockType)
+}
+
+func (d *fakeDevice) GetApplicationsClock(clockType nvml.ClockType) (uint32, nvml.Return) {
+	return d.clock("GetApplicationsClock", d.cfg.ClocksApplications, clockType)
+}
+
+func (d *fakeDevice) GetDefaultApplicationsClock(clockType nvml.ClockType) (uint32, nvml.Return) {
+	return d.clock("GetDefaultApplicationsClock", d.cfg.ClocksDefaultApplications, clockType)
+}
+
+func (d *fakeDevice) GetMinMaxClockOfPState(clockType nvml.ClockType, pstate nvml.Pstates) (uint32, uint32, nvml.Return) {
+	if ret := d.ret("GetMinMaxClockOfPState"); ret != nvml.SUCCESS {
+		return 0, 0, ret
+	}
+	for _, t := range clockTypes {
+		if clocks, ok := d.cfg.PstateClocks[t.name]; ok && t.clockType == clockType {
+			return clocks[0], clocks[1], nvml.SUCCESS
+		}
+	}
+	return 0, 0, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (d *fakeDevice) GetPerformanceState() (nvml.Pstates, nvml.Return) {
+	if d.cfg.Pstate == nil {
+		return nvml.PSTATE_UNKNOWN, d.ret("GetPerformanceState")
+	}
+	return nvml.Pstates(*d.cfg.Pstate), d.ret("GetPerformanceState")
+}
+
+func (d *fakeDevice) GetDynamicPstatesInfo() (nvml.GpuDynamicPstatesInfo, nvml.Return) {
+	var info nvml.GpuDynamicPstatesInfo
+	if ret := d.ret("GetDynamicPstatesInfo"); ret != nvml.SUCCESS {
+		return info, ret
+	}
+	if len(d.cfg.DynamicPstates) == 0 {
+		return info, nvml.ERROR_NOT_SUPPORTED
+	}
+	for _, domain := range utilizationDomains {
+		if percentage, ok := d.cfg.DynamicPstates[domain.name]; ok {
+			info.Utilization[domain.domain] = nvml.GpuDynamicPstatesInfoUtilization{
+				BIsPresent: 1,
+				Percentage: percentage,
+			}
+		}
+	}
+	return info, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetViolationStatus(perfPolicyType nvml.PerfPolicyType) (nvml.ViolationTime, nvml.Return) {
+	if ret := d.ret("GetViolationStatus"); ret != nvml.SUCCESS {
+		return nvml.ViolationTime{}, ret
+	}
+	for _, p := range perfPolicies {
+		if seconds, ok := d.cfg.Violations[p.name]; ok && p.policy == perfPolicyType {
+			return nvml.ViolationTime{
+				ReferenceTime: uint64(time.Now().UnixNano()),
+				ViolationTime: uint64(seconds * float64(time.Second)),
+			}, nvml.SUCCESS
+		}
+	}
+	return nvml.ViolationTime{}, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (d *fakeDevice) GetNumFans() (int, nvml.Return) {
+	return len(d.cfg.Fans), d.ret("GetNumFans")
+}
+
+// fan returns the configuration of a fan and the return code of call
+func (d *fakeDevice) fan(call string, fan int) (*fakeFan, nvml.Return) {
+	if ret := d.ret(call); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	if fan < 0 || fan >= len(d.cfg.Fans) {
+		return nil, nvml.ERROR_INVALID_ARGUMENT
+	}
+	return &d.cfg.Fans[fan], nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetFanSpeed_v2(fan int) (uint32, nvml.Return) {
+	f, ret := d.fan("GetFanSpeed_v2", fan)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	return f.Speed, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetTargetFanSpeed(fan int) (int, nvml.Return) {
+	f, ret := d.fan("GetTargetFanSpeed", fan)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	return f.Target, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetFanControlPolicy_v2(fan int) (nvml.FanControlPolicy, nvml.Return) {
+	f, ret := d.fan("GetFanControlPolicy_v2", fan)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	for policy, name := range fanControlPolicies {
+		if name == f.Policy {
+			return policy, nvml.SUCCESS
+		}
+	}
+	return nvml.FAN_POLICY_TEMPERATURE_CONTINOUS_SW, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetMinMaxFanSpeed() (int, int, nvml.Return) {
+	if len(d.cfg.Fans) == 0 {
+		return 0, 0, nvml.ERROR_NOT_SUPPORTED
+	}
+	return d.cfg.FanSpeedMin, d.cfg.FanSpeedMax, d.ret("GetMinMaxFanSpeed")
+}
+
+func (d *fakeDevice) GetPowerManagementLimit() (uint32, nvml.Return) {
+	if d.cfg.PowerManagementLimit == 0 {
+		return d.cfg.PowerLimit, d.ret("GetPowerManagementLimit")
+	}
+	return d.cfg.PowerManagementLimit, d.ret("GetPowerManagementLimit")
+}
+
+func (d *fakeDevice) GetPowerManagementDefaultLimit() (uint32, nvml.Return) {
+	if d.cfg.PowerDefaultLimit == 0 {
+		return d.cfg.PowerLimit, d.ret("GetPowerManagementDefaultLimit")
+	}
+	return d.cfg.PowerDefaultLimit, d.ret("GetPowerManagementDefaultLimit")
+}
+
+func (d *fakeDevice) GetPowerManagementLimitConstraints() (uint32, uint32, nvml.Return) {
+	return d.cfg.PowerLimitMin, d.cfg.PowerLimitMax, d.ret("GetPowerManagementLimitConstraints")
+}
+
+func (d *fakeDevice) GetPowerManagementMode() (nvml.EnableState, nvml.Return) {
+	return fakeEnableState(d.cfg.PowerManagementMode), d.ret("GetPowerManagementMode")
+}
+
+func (d *fakeDevice) GetPowerSource() (nvml.PowerSource, nvml.Return) {
+	for source, name := range powerSources {
+		if name == d.cfg.PowerSource {
+			return source, d.ret("GetPowerSource")
+		}
+	}
+	return nvml.POWER_SOURCE_AC, d.ret("GetPowerSource")
+}
+
+func (d *fakeDevice) GetPowerState() (nvml.Pstates, nvml.Return) {
+	if d.cfg.Pstate == nil {
+		return nvml.PSTATE_UNKNOWN, d.ret("GetPowerState")
+	}
+	return nvml.Pstates(*d.cfg.Pstate), d.ret("GetPowerState")
+}
+
+func (d *fakeDevice) GetMigMode() (int, int, nvml.Return) {
+	if d.cfg.Mig == nil {
+		return 0, 0, nvml.ERROR_NOT_SUPPORTED
+	}
+	current, pending := nvml.DEVICE_MIG_DISABLE, nvml.DEVICE_MIG_DISABLE
+	if d.cfg.Mig.Enabled {
+		current = nvml.DEVICE_MIG_ENABLE
+	}
+	if d.cfg.Mig.Pending {
+		pending = nvml.DEVICE_MIG_ENABLE
+	}
+	return current, pending, d.ret("GetMigMode")
+}
+
+func (d *fakeDevice) GetMaxMigDeviceCount() (int, nvml.Return) {
+	if d.cfg.Mig == nil {
+		return 0, nvml.ERROR_NOT_SUPPORTED
+	}
+	return d.cfg.Mig.MaxDevices, d.ret("GetMaxMigDeviceCount")
+}
+
+func (d *fakeDevice) GetMigDeviceHandleByIndex(index int) (nvml.Device, nvml.Return) {
+	if ret := d.ret("GetMigDeviceHandleByIndex"); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	if d.cfg.Mig == nil || !d.cfg.Mig.Enabled {
+		return nil, nvml.ERROR_NOT_SUPPORTED
+	}
+	if index < 0 || index >= d.cfg.Mig.MaxDevices {
+		return nil, nvml.ERROR_INVALID_ARGUMENT
+	}
+	if index >= len(d.cfg.Mig.Devices) {
+		return nil, nvml.ERROR_NOT_FOUND
+	}
+	return &fakeMigHandle{cfg: &d.cfg.Mig.Devices[index]}, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetGpuInstanceProfileInfo(profile int) (nvml.GpuInstanceProfileInfo, nvml.Return) {
+	if d.cfg.Mig == nil {
+		return nvml.GpuInstanceProfileInfo{}, nvml.ERROR_NOT_SUPPORTED
+	}
+	p, ok := d.cfg.Mig.GpuInstanceProfiles[profile]
+	if !ok {
+		return nvml.GpuInstanceProfileInfo{}, nvml.ERROR_NOT_SUPPORTED
+	}
+	info := nvml.GpuInstanceProfileInfo{Id: uint32(profile), SliceCount: p.Slices, MemorySizeMB: p.MemoryMB}
+	info.InstanceCount = uint32(len(d.gpuInstances(profile)))
+	return info, d.ret("GetGpuInstanceProfileInfo")
+}
+
+func (d *fakeDevice) GetGpuInstances(info *nvml.GpuInstanceProfileInfo) ([]nvml.GpuInstance, nvml.Return) {
+	if ret := d.ret("GetGpuInstances"); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	return d.gpuInstances(int(info.Id)), nvml.SUCCESS
+}
+
+// gpuInstances returns the GPU instances with the given profile
+func (d *fakeDevice) gpuInstances(profile int) []nvml.GpuInstance {
+	var instances []nvml.GpuInstance
+	seen := make(map[uint32]bool)
+	for _, m := range d.cfg.Mig.Devices {
+		if m.GpuInstanceProfile == profile && !seen[m.GpuInstance] {
+			seen[m.GpuInstance] = true
+			instances = append(instances, &fakeGpuInstance{mig: d.cfg.Mig, id: m.GpuInstance, profile: profile})
+		}
+	}
+	return instances
+}
+
+// fakeMigHandle implements nvml.Device for a MIG device
+type fakeMigHandle struct {
+	unsupportedDevice
+	cfg *fakeMigDevice
+}
+
+func (m *fakeMigHandle) GetUUID() (string, nvml.Return) {
+	return m.cfg.UUID, nvml.SUCCESS
+}
+
+func (m *fakeMigHandle) GetGpuInstanceId() (int, nvml.Return) {
+	return int(m.cfg.GpuInstance), nvml.SUCCESS
+}
+
+func (m *fakeMigHandle) GetComputeInstanceId() (int, nvml.Return) {
+	return int(m.cfg.ComputeInstance), nvml.SUCCESS
+}
+
+func (m *fakeMigHandle) GetMemoryInfo() (nvml.Memory, nvml.Return) {
+	return nvml.Memory{
+		Total: m.cfg.MemoryTotal,
+		Used:  m.cfg.MemoryUsed,
+		Free:  m.cfg.MemoryTotal - m.cfg.MemoryUsed,
+	}, nvml.SUCCESS
+}
+
+// GetUtilizationRates isn't supported on MIG devices, like on real hardware
+func (m *fakeMigHandle) GetUtilizationRates() (nvml.Utilization, nvml.Return) {
+	return nvml.Utilization{}, nvml.ERROR_NOT_SUPPORTED
+}
+
+func (m *fakeMigHandle) GetComputeRunningProcesses() ([]nvml.ProcessInfo, nvml.Return) {
+	var processes []nvml.ProcessInfo
+	for _, p := range m.cfg.Processes {
+		processes = append(processes, nvml.ProcessInfo{
+			Pid:               p.PID,
+			UsedGpuMemory:     p.UsedMemory,
+			GpuInstanceId:     m.cfg.GpuInstance,
+			ComputeInstanceId: m.cfg.ComputeInstance,
+		})
+	}
+	return processes, nvml.SUCCESS
+}
+
+// fakeGpuInstance implements nvml.GpuInstance
+type fakeGpuInstance struct {
+	unsupportedGpuInstance
+	mig     *fakeMig
+	id      uint32
+	profile int
+}
+
+func (g *fakeGpuInstance) GetInfo() (nvml.GpuInstanceInfo, nvml.Return) {
+	return nvml.GpuInstanceInfo{Id: g.id, ProfileId: uint32(g.profile)}, nvml.SUCCESS
+}
+
+func (g *fakeGpuInstance) GetComputeInstanceProfileInfo(profile int, engProfile int) (nvml.ComputeInstanceProfileInfo, nvml.Return) {
+	p, ok := g.mig.ComputeInstanceProfiles[profile]
+	if !ok {
+		return nvml.ComputeInstanceProfileInfo{}, nvml.ERROR_NOT_SUPPORTED
+	}
+	info := nvml.ComputeInstanceProfileInfo{Id: uint32(profile), SliceCount: p.Slices}
+	info.InstanceCount = uint32(len(g.computeInstances(profile)))
+	return info, nvml.SUCCESS
+}
+
+func (g *fakeGpuInstance) GetComputeInstances(info *nvml.ComputeInstanceProfileInfo) ([]nvml.ComputeInstance, nvml.Return) {
+	return g.computeInstances(int(info.Id)), nvml.SUCCESS
+}
+
+// computeInstances returns the compute instances of g with the given profile
+func (g *fakeGpuInstance) computeInstances(profile int) []nvml.ComputeInstance {
+	var instances []nvml.ComputeInstance
+	for _, m := range g.mig.Devices {
+		if m.GpuInstance == g.id && m.ComputeInstanceProfile == profile {
+			instances = append(instances, &fakeComputeInstance{id: m.ComputeInstance})
+		}
+	}
+	return instances
+}
+
+// fakeComputeInstance implements nvml.ComputeInstance
+type fakeComputeInstance struct {
+	unsupportedComputeInstance
+	id uint32
+}
+
+func (c *fakeComputeInstance) GetInfo() (nvml.ComputeInstanceInfo, nvml.Return) {
+	return nvml.ComputeInstanceInfo{Id: c.id}, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetVirtualizationMode() (nvml.GpuVirtualizationMode, nvml.Return) {
+	for mode, name := range virtualizationModes {
+		if name == d.cfg.VirtualizationMode {
+			return mode, d.ret("GetVirtualizationMode")
+		}
+	}
+	return nvml.GPU_VIRTUALIZATION_MODE_NONE, d.ret("GetVirtualizationMode")
+}
+
+func (d *fakeDevice) GetActiveVgpus() ([]nvml.VgpuInstance, nvml.Return) {
+	var instances []nvml.VgpuInstance
+	for _, v := range d.cfg.Vgpus {
+		instances = append(instances, fakeVgpuInstance(v.ID))
+	}
+	return instances, d.ret("GetActiveVgpus")
+}
+
+func (d *fakeDevice) GetVgpuUtilization(lastSeenTimestamp uint64) (nvml.ValueType, []nvml.VgpuInstanceUtilizationSample, nvml.Return) {
+	if ret := d.ret("GetVgpuUtilization"); ret != nvml.SUCCESS {
+		return 0, nil, ret
+	}
+	if len(d.cfg.Vgpus) == 0 {
+		return 0, nil, nvml.ERROR_NOT_FOUND
+	}
+	value := func(v uint32) [8]byte {
+		var b [8]byte
+		binary.NativeEndian.PutUint32(b[:], v)
+		return b
+	}
+	now := uint64(time.Now().UnixMicro())
+	var samples []nvml.VgpuInstanceUtilizationSample
+	for _, v := range d.cfg.Vgpus {
+		samples = append(samples, nvml.VgpuInstanceUtilizationSample{
+			VgpuInstance: v.ID,
+			TimeStamp:    now,
+			SmUtil:       value(v.SMUtil),
+			MemUtil:      value(v.MemUtil),
+			EncUtil:      value(v.EncUtil),
+			DecUtil:      value(v.DecUtil),
+		})
+	}
+	return nvml.VALUE_TYPE_UNSIGNED_INT, samples, nvml.SUCCESS
+}
+
+// fakeVgpus holds the configuration of every vGPU instance of the last
+// loaded fixture by ID. The NVML handle of a vGPU instance is its ID,
+// fakeVgpuInstance is the same so the exporter can match it with utilization
+// samples.
+var fakeVgpus atomic.Pointer[map[fakeVgpuInstance]*fakeVgpu]
+
+// fakeVgpuInstance implements nvml.VgpuInstance
+type fakeVgpuInstance uint32
+
+// cfg returns the configuration of the instance, or ERROR_INVALID_ARGUMENT
+// like NVML if the instance is no longer in the fixture
+func (v fakeVgpuInstance) cfg() (*fakeVgpu, nvml.Return) {
+	if vgpus := fakeVgpus.Load(); vgpus != nil {
+		if cfg, ok := (*vgpus)[v]; ok {
+			return cfg, nvml.SUCCESS
+		}
+	}
+	return nil, nvml.ERROR_INVALID_ARGUMENT
+}
+
+// VgpuInstance methods which aren't implemented return ERROR_NOT_SUPPORTED,
+// they are written out since fakeVgpuInstance can't embed the stubs
+func (v fakeVgpuInstance) ClearAccountingPids() nvml.Return { return nvml.ERROR_NOT_SUPPORTED }
+func (v fakeVgpuInstance) GetAccountingMode() (nvml.EnableState, nvml.Return) {
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetAccountingPids() ([]int, nvml.Return) {
+	return nil, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetAccountingStats(int) (nvml.AccountingStats, nvml.Return) {
+	return nvml.AccountingStats{}, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetEccMode() (nvml.EnableState, nvml.Return) {
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetEncoderCapacity() (int, nvml.Return) { return 0, nvml.ERROR_NOT_SUPPORTED }
+func (v fakeVgpuInstance) GetEncoderSessions() (int, nvml.EncoderSessionInfo, nvml.Return) {
+	return 0, nvml.EncoderSessionInfo{}, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetFBCSessions() (int, nvml.FBCSessionInfo, nvml.Return) {
+	return 0, nvml.FBCSessionInfo{}, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetFrameRateLimit() (uint32, nvml.Return) {
+	return 0, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetGpuInstanceId() (int, nvml.Return) { return 0, nvml.ERROR_NOT_SUPPORTED }
+func (v fakeVgpuInstance) GetGpuPciId() (string, nvml.Return)   { return "", nvml.ERROR_NOT_SUPPORTED }
+func (v fakeVgpuInstance) GetMdevUUID() (string, nvml.Return)   { return "", nvml.ERROR_NOT_SUPPORTED }
+func (v fakeVgpuInstance) GetMetadata() (nvml.VgpuMetadata, nvml.Return) {
+	return nvml.VgpuMetadata{}, nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) GetVmDriverVersion() (string, nvml.Return) {
+	return "", nvml.ERROR_NOT_SUPPORTED
+}
+func (v fakeVgpuInstance) SetEncoderCapacity(int) nvml.Return { return nvml.ERROR_NOT_SUPPORTED }
+
+func (v fakeVgpuInstance) GetUUID() (string, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return "", ret
+	}
+	return cfg.UUID, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetVmID() (string, nvml.VgpuVmIdType, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return "", 0, ret
+	}
+	return cfg.VMID, nvml.VGPU_VM_ID_UUID, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetType() (nvml.VgpuTypeId, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	return &fakeVgpuType{name: cfg.Type}, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetFbUsage() (uint64, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	return cfg.FbUsage, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetLicenseStatus() (int, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	if cfg.Licensed {
+		return 1, nvml.SUCCESS
+	}
+	return 0, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetEncoderStats() (int, uint32, uint32, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return 0, 0, 0, ret
+	}
+	return int(cfg.EncoderSessions), cfg.EncoderFPS, cfg.EncoderLatency, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetFBCStats() (nvml.FBCStats, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return nvml.FBCStats{}, ret
+	}
+	return nvml.FBCStats{SessionsCount: cfg.FBCSessions, AverageFPS: cfg.FBCFPS, AverageLatency: cfg.FBCLatency}, nvml.SUCCESS
+}
+
+// fakeVgpuType implements nvml.VgpuTypeId
+type fakeVgpuType struct {
+	unsupportedVgpuTypeId
+	name string
+}
+
+func (t *fakeVgpuType) GetName() (string, nvml.Return) {
+	return t.name, nvml.SUCCESS
+}
+
+func (v fakeVgpuInstance) GetLicenseInfo() (nvml.VgpuLicenseInfo, nvml.Return) {
+	cfg, ret := v.cfg()
+	if ret != nvml.SUCCESS {
+		return nvml.VgpuLicenseInfo{}, ret
+	}
+	info := nvml.VgpuLicenseInfo{
+		LicenseExpiry: nvml.VgpuLicenseExpiry(cfg.LicenseExpiry.expiry()),
+		CurrentState:  nvml.GRID_LICENSE_STATE_UNLICENSED,
+	}
+	if cfg.Licensed {
+		info.IsLicensed = 1
+		info.CurrentState = nvml.GRID_LICENSE_STATE_LICENSED
+	}
+	for state, name := range gridLicenseStates {
+		if name == cfg.LicenseState {
+			info.CurrentState = state
+		}
+	}
+	return info, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetGridLicensableFeatures() (nvml.GridLicensableFeatures, nvml.Return) {
+	var features nvml.GridLicensableFeatures
+	if ret := d.ret("GetGridLicensableFeatures"); ret != nvml.SUCCESS {
+		return features, ret
+	}
+	if len(d.cfg.GridFeatures) == 0 {
+		return features, nvml.SUCCESS
+	}
+	features.IsGridLicenseSupported = 1
+	for i, f := range d.cfg.GridFeatures[:min(len(d.cfg.GridFeatures), len(features.GridLicensableFeatures))] {
+		feature := &features.GridLicensableFeatures[i]
+		if code, err := strconv.ParseUint(f.Feature, 10, 32); err == nil {
+			feature.FeatureCode = uint32(code)
+		}
+		for code, name := range gridLicenseFeatures {
+			if name == f.Feature {
+				feature.FeatureCode = code
+			}
+		}
+		feature.FeatureState = uint32(boolFloat(f.Licensed))
+		feature.FeatureEnabled = uint32(boolFloat(f.Enabled))
+		feature.LicenseExpiry = f.Expiry.expiry()
+		features.LicensableFeaturesCount++
+	}
+	return features, nvml.SUCCESS
+}
+
+// fakeGpmSample implements nvml.GpmSample, values are the metrics returned
+// when it's the second sample of GpmMetricsGet
+type fakeGpmSample struct {
+	taken  bool
+	values map[uint32]float64
+}
+
+func (s *fakeGpmSample) Free() nvml.Return {
+	return nvml.SUCCESS
+}
+
+func (s *fakeGpmSample) Get(device nvml.Device) nvml.Return {
+	return device.GpmSampleGet(s)
+}
+
+func (s *fakeGpmSample) MigGet(device nvml.Device, gpuInstanceId int) nvml.Return {
+	return device.GpmMigSampleGet(gpuInstanceId, s)
+}
+
+func (f *fakeNvml) GpmSampleAlloc() (nvml.GpmSample, nvml.Return) {
+	return &fakeGpmSample{}, f.ret("GpmSampleAlloc")
+}
+
+func (f *fakeNvml) GpmMetricsGet(metrics *nvml.GpmMetricsGetType) nvml.Return {
+	if ret := f.ret("GpmMetricsGet"); ret != nvml.SUCCESS {
+		return ret
+	}
+	sample1, ok1 := metrics.Sample1.(*fakeGpmSample)
+	sample2, ok2 := metrics.Sample2.(*fakeGpmSample)
+	if !ok1 || !ok2 || !sample1.taken || !sample2.taken || metrics.NumMetrics > uint32(len(metrics.Metrics)) {
+		return nvml.ERROR_INVALID_ARGUMENT
+	}
+	for i := range metrics.NumMetrics {
+		m := &metrics.Metrics[i]
+		value, ok := sample2.values[m.MetricId]
+		if !ok {
+			m.NvmlReturn = uint32(nvml.ERROR_NOT_SUPPORTED)
+			continue
+		}
+		m.Value = value
+		m.NvmlReturn = uint32(nvml.SUCCESS)
+	}
+	return nvml.SUCCESS
+}
+
+func (d *fakeDevice) GpmQueryDeviceSupport() (nvml.GpmSupport, nvml.Return) {
+	support := nvml.GpmSupport{Version: nvml.GPM_SUPPORT_VERSION}
+	if d.cfg.Gpm != nil {
+		support.IsSupportedDevice = 1
+	}
+	return support, d.ret("GpmQueryDeviceSupport")
+}
+
+func (d *fakeDevice) GpmSampleGet(sample nvml.GpmSample) nvml.Return {
+	if ret := d.ret("GpmSampleGet"); ret != nvml.SUCCESS {
+		return ret
+	}
+	if d.cfg.Gpm == nil {
+		return nvml.ERROR_NOT_SUPPORTED
+	}
+	s := sample.(*fakeGpmSample)
+	s.taken = true
+	s.values = d.cfg.Gpm
+	return nvml.SUCCESS
+}
+
+func (d *fakeDevice) GpmMigSampleGet(gpuInstanceId int, sample nvml.GpmSample) nvml.Return {
+	if ret := d.ret("GpmMigSampleGet"); ret != nvml.SUCCESS {
+		return ret
+	}
+	if d.cfg.Gpm == nil || d.cfg.Mig == nil || !d.cfg.Mig.Enabled {
+		return nvml.ERROR_NOT_SUPPORTED
+	}
+	s := sample.(*fakeGpmSample)
+	s.values = nil
+	found := false
+	for _, m := range d.cfg.Mig.Devices {
+		if int(m.GpuInstance) == gpuInstanceId {
+			found = true
+			if m.Gpm != nil {
+				s.values = m.Gpm
+				break
+			}
+		}
+	}
+	if !found {
+		return nvml.ERROR_INVALID_ARGUMENT
+	}
+	s.taken = true
+	return nvml.SUCCESS
+}
+
+// fakeSessionStats returns the number of sessions and their average frame
+// rate and latency
+func fakeSessionStats(sessions []fakeEncoderSession) (uint32, uint32, uint32) {
+	if len(sessions) == 0 {
+		return 0, 0, 0
+	}
+	var fps, latency uint32
+	for _, s := range sessions {
+		fps += s.FPS
+		latency += s.Latency
+	}
+	count := uint32(len(sessions))
+	return count, fps / count, latency / count
+}
+
+func (d *fakeDevice) GetEncoderStats() (int, uint32, uint32, nvml.Return) {
+	count, fps, latency := fakeSessionStats(d.cfg.EncoderSessions)
+	return int(count), fps, latency, d.ret("GetEncoderStats")
+}
+
+func (d *fakeDevice) GetEncoderCapacity(encoderQueryType nvml.EncoderType) (int, nvml.Return) {
+	capacity, ok := d.cfg.EncoderCapacity[encoderCodecs[encoderQueryType]]
+	if !ok {
+		return 0, nvml.ERROR_NOT_SUPPORTED
+	}
+	return capacity, d.ret("GetEncoderCapacity")
+}
+
+func (d *fakeDevice) GetFBCStats() (nvml.FBCStats, nvml.Return) {
+	count, fps, latency := fakeSessionStats(d.cfg.FBCSessions)
+	return nvml.FBCStats{SessionsCount: count, AverageFPS: fps, AverageLatency: latency}, d.ret("GetFBCStats")
+}
+
+func (d *fakeDevice) GetEncoderSessions() ([]nvml.EncoderSessionInfo, nvml.Return) {
+	if ret := d.ret("GetEncoderSessions"); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	var sessions []nvml.EncoderSessionInfo
+	for _, s := range d.cfg.EncoderSessions {
+		info := nvml.EncoderSessionInfo{
+			SessionId:      s.ID,
+			Pid:            s.PID,
+			CodecType:      uint32(nvml.ENCODER_QUERY_UNKNOWN),
+			HResolution:    s.Width,
+			VResolution:    s.Height,
+			AverageFps:     s.FPS,
+			AverageLatency: s.Latency,
+		}
+		for codec, name := range encoderCodecs {
+			if name == s.Type {
+				info.CodecType = uint32(codec)
+			}
+		}
+		sessions = append(sessions, info)
+	}
+	return sessions, nvml.SUCCESS
+}
+
+func (d *fakeDevice) GetFBCSessions() ([]nvml.FBCSessionInfo, nvml.Return) {
+	if ret := d.ret("GetFBCSessions"); ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	var sessions []nvml.FBCSessionInfo
+	for _, s := range d.cfg.FBCSessions {
+		info := nvml.FBCSessionInfo{
+			SessionId:      s.ID,
+			Pid:            s.PID,
+			HMaxResolution: s.Width,
+			VMaxResolution: s.Height,
+			HResolution:    s.Width,
+			VResolution:    s.Height,
+			AverageFPS:     s.FPS,
+			AverageLatency: s.Latency,
+		}
+		for sessionType, name := range fbcSessionTypes {
+			if name == s.Type {
+				info.SessionType = uint32(sessionType)
+			}
+		}
+		sessions = append(sessions, info)
+	}
+	return sessions, nvml.SUCCESS
+}
@@ -0,0 +1,2877 @@
+// Code generated by gen_unsupported.go. DO NOT EDIT.
+
+package main
+
+import "github.com/NVIDIA/go-nvml/pkg/nvml"
+
+// unsupportedInterface implements nvml.Interface, every call returns ERROR_NOT_SUPPORTED
+type unsupportedInterface struct{}
+
+func (unsupportedInterface) ComputeInstanceDestroy(nvml.ComputeInstance) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) ComputeInstanceGetInfo(nvml.ComputeInstance) (_ nvml.ComputeInstanceInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceClearAccountingPids(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceClearCpuAffinity(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceClearEccErrorCounts(nvml.Device, nvml.EccCounterType) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceClearFieldValues(nvml.Device, []nvml.FieldValue) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceCreateGpuInstance(nvml.Device, *nvml.GpuInstanceProfileInfo) (_ nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceCreateGpuInstanceWithPlacement(nvml.Device, *nvml.GpuInstanceProfileInfo, *nvml.GpuInstancePlacement) (_ nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceDiscoverGpus() (_ nvml.PciInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceFreezeNvLinkUtilizationCounter(nvml.Device, int, int, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAPIRestriction(nvml.Device, nvml.RestrictedAPI) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAccountingBufferSize(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAccountingMode(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAccountingPids(nvml.Device) (_ []int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAccountingStats(nvml.Device, uint32) (_ nvml.AccountingStats, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetActiveVgpus(nvml.Device) (_ []nvml.VgpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAdaptiveClockInfoStatus(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetApplicationsClock(nvml.Device, nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetArchitecture(nvml.Device) (_ nvml.DeviceArchitecture, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAttributes(nvml.Device) (_ nvml.DeviceAttributes, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetAutoBoostedClocksEnabled(nvml.Device) (_ nvml.EnableState, _ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetBAR1MemoryInfo(nvml.Device) (_ nvml.BAR1Memory, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetBoardId(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetBoardPartNumber(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetBrand(nvml.Device) (_ nvml.BrandType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetBridgeChipInfo(nvml.Device) (_ nvml.BridgeChipHierarchy, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetBusType(nvml.Device) (_ nvml.BusType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetC2cModeInfoV(nvml.Device) (_ nvml.C2cModeInfoHandler) {
+	return
+}
+
+func (unsupportedInterface) DeviceGetClkMonStatus(nvml.Device) (_ nvml.ClkMonStatus, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetClock(nvml.Device, nvml.ClockType, nvml.ClockId) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetClockInfo(nvml.Device, nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetComputeInstanceId(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetComputeMode(nvml.Device) (_ nvml.ComputeMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetComputeRunningProcesses(nvml.Device) (_ []nvml.ProcessInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetConfComputeGpuAttestationReport(nvml.Device) (_ nvml.ConfComputeGpuAttestationReport, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetConfComputeGpuCertificate(nvml.Device) (_ nvml.ConfComputeGpuCertificate, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetConfComputeMemSizeInfo(nvml.Device) (_ nvml.ConfComputeMemSizeInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetConfComputeProtectedMemoryUsage(nvml.Device) (_ nvml.Memory, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCount() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCpuAffinity(nvml.Device, int) (_ []uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCpuAffinityWithinScope(nvml.Device, int, nvml.AffinityScope) (_ []uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCreatableVgpus(nvml.Device) (_ []nvml.VgpuTypeId, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCudaComputeCapability(nvml.Device) (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCurrPcieLinkGeneration(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCurrPcieLinkWidth(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCurrentClocksEventReasons(nvml.Device) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetCurrentClocksThrottleReasons(nvml.Device) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDecoderUtilization(nvml.Device) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDefaultApplicationsClock(nvml.Device, nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDefaultEccMode(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDetailedEccErrors(nvml.Device, nvml.MemoryErrorType, nvml.EccCounterType) (_ nvml.EccErrorCounts, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDeviceHandleFromMigDeviceHandle(nvml.Device) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDisplayActive(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDisplayMode(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDriverModel(nvml.Device) (_ nvml.DriverModel, _ nvml.DriverModel, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetDynamicPstatesInfo(nvml.Device) (_ nvml.GpuDynamicPstatesInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetEccMode(nvml.Device) (_ nvml.EnableState, _ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetEncoderCapacity(nvml.Device, nvml.EncoderType) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetEncoderSessions(nvml.Device) (_ []nvml.EncoderSessionInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetEncoderStats(nvml.Device) (_ int, _ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetEncoderUtilization(nvml.Device) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetEnforcedPowerLimit(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetFBCSessions(nvml.Device) (_ []nvml.FBCSessionInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetFBCStats(nvml.Device) (_ nvml.FBCStats, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetFanControlPolicy_v2(nvml.Device, int) (_ nvml.FanControlPolicy, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetFanSpeed(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetFanSpeed_v2(nvml.Device, int) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetFieldValues(nvml.Device, []nvml.FieldValue) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpcClkMinMaxVfOffset(nvml.Device) (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpcClkVfOffset(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuFabricInfo(nvml.Device) (_ nvml.GpuFabricInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuFabricInfoV(nvml.Device) (_ nvml.GpuFabricInfoHandler) {
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstanceById(nvml.Device, int) (_ nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstanceId(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstancePossiblePlacements(nvml.Device, *nvml.GpuInstanceProfileInfo) (_ []nvml.GpuInstancePlacement, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstanceProfileInfo(nvml.Device, int) (_ nvml.GpuInstanceProfileInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstanceProfileInfoV(nvml.Device, int) (_ nvml.GpuInstanceProfileInfoHandler) {
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstanceRemainingCapacity(nvml.Device, *nvml.GpuInstanceProfileInfo) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuInstances(nvml.Device, *nvml.GpuInstanceProfileInfo) (_ []nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuMaxPcieLinkGeneration(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGpuOperationMode(nvml.Device) (_ nvml.GpuOperationMode, _ nvml.GpuOperationMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGraphicsRunningProcesses(nvml.Device) (_ []nvml.ProcessInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGridLicensableFeatures(nvml.Device) (_ nvml.GridLicensableFeatures, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGspFirmwareMode(nvml.Device) (_ bool, _ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetGspFirmwareVersion(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetHandleByIndex(int) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetHandleByPciBusId(string) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetHandleBySerial(string) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetHandleByUUID(string) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetHostVgpuMode(nvml.Device) (_ nvml.HostVgpuMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetIndex(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetInforomConfigurationChecksum(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetInforomImageVersion(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetInforomVersion(nvml.Device, nvml.InforomObject) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetIrqNum(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetJpgUtilization(nvml.Device) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetLastBBXFlushTime(nvml.Device) (_ uint64, _ uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMPSComputeRunningProcesses(nvml.Device) (_ []nvml.ProcessInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMaxClockInfo(nvml.Device, nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMaxCustomerBoostClock(nvml.Device, nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMaxMigDeviceCount(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMaxPcieLinkGeneration(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMaxPcieLinkWidth(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemClkMinMaxVfOffset(nvml.Device) (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemClkVfOffset(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemoryAffinity(nvml.Device, int, nvml.AffinityScope) (_ []uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemoryBusWidth(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemoryErrorCounter(nvml.Device, nvml.MemoryErrorType, nvml.EccCounterType, nvml.MemoryLocation) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemoryInfo(nvml.Device) (_ nvml.Memory, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMemoryInfo_v2(nvml.Device) (_ nvml.Memory_v2, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMigDeviceHandleByIndex(nvml.Device, int) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMigMode(nvml.Device) (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMinMaxClockOfPState(nvml.Device, nvml.ClockType, nvml.Pstates) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMinMaxFanSpeed(nvml.Device) (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMinorNumber(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetModuleId(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetMultiGpuBoard(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetName(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNumFans(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNumGpuCores(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNumaNodeId(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkCapability(nvml.Device, int, nvml.NvLinkCapability) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkErrorCounter(nvml.Device, int, nvml.NvLinkErrorCounter) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkRemoteDeviceType(nvml.Device, int) (_ nvml.IntNvLinkDeviceType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkRemotePciInfo(nvml.Device, int) (_ nvml.PciInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkState(nvml.Device, int) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkUtilizationControl(nvml.Device, int, int) (_ nvml.NvLinkUtilizationControl, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkUtilizationCounter(nvml.Device, int, int) (_ uint64, _ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetNvLinkVersion(nvml.Device, int) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetOfaUtilization(nvml.Device) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetP2PStatus(nvml.Device, nvml.Device, nvml.GpuP2PCapsIndex) (_ nvml.GpuP2PStatus, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPciInfo(nvml.Device) (_ nvml.PciInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPciInfoExt(nvml.Device) (_ nvml.PciInfoExt, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPcieLinkMaxSpeed(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPcieReplayCounter(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPcieSpeed(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPcieThroughput(nvml.Device, nvml.PcieUtilCounter) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPerformanceState(nvml.Device) (_ nvml.Pstates, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPersistenceMode(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPgpuMetadataString(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerManagementDefaultLimit(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerManagementLimit(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerManagementLimitConstraints(nvml.Device) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerManagementMode(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerSource(nvml.Device) (_ nvml.PowerSource, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerState(nvml.Device) (_ nvml.Pstates, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetPowerUsage(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetProcessUtilization(nvml.Device, uint64) (_ []nvml.ProcessUtilizationSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetProcessesUtilizationInfo(nvml.Device) (_ nvml.ProcessesUtilizationInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetRemappedRows(nvml.Device) (_ int, _ int, _ bool, _ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetRetiredPages(nvml.Device, nvml.PageRetirementCause) (_ []uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetRetiredPagesPendingStatus(nvml.Device) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetRetiredPages_v2(nvml.Device, nvml.PageRetirementCause) (_ []uint64, _ []uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetRowRemapperHistogram(nvml.Device) (_ nvml.RowRemapperHistogramValues, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetRunningProcessDetailList(nvml.Device) (_ nvml.ProcessDetailList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSamples(nvml.Device, nvml.SamplingType, uint64) (_ nvml.ValueType, _ []nvml.Sample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSerial(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSramEccErrorStatus(nvml.Device) (_ nvml.EccSramErrorStatus, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedClocksEventReasons(nvml.Device) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedClocksThrottleReasons(nvml.Device) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedEventTypes(nvml.Device) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedGraphicsClocks(nvml.Device, int) (_ int, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedMemoryClocks(nvml.Device) (_ int, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedPerformanceStates(nvml.Device) (_ []nvml.Pstates, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetSupportedVgpus(nvml.Device) (_ []nvml.VgpuTypeId, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTargetFanSpeed(nvml.Device, int) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTemperature(nvml.Device, nvml.TemperatureSensors) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTemperatureThreshold(nvml.Device, nvml.TemperatureThresholds) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetThermalSettings(nvml.Device, uint32) (_ nvml.GpuThermalSettings, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTopologyCommonAncestor(nvml.Device, nvml.Device) (_ nvml.GpuTopologyLevel, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTopologyNearestGpus(nvml.Device, nvml.GpuTopologyLevel) (_ []nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTotalEccErrors(nvml.Device, nvml.MemoryErrorType, nvml.EccCounterType) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetTotalEnergyConsumption(nvml.Device) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetUUID(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetUtilizationRates(nvml.Device) (_ nvml.Utilization, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVbiosVersion(nvml.Device) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuCapabilities(nvml.Device, nvml.DeviceVgpuCapability) (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuHeterogeneousMode(nvml.Device) (_ nvml.VgpuHeterogeneousMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuInstancesUtilizationInfo(nvml.Device) (_ nvml.VgpuInstancesUtilizationInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuMetadata(nvml.Device) (_ nvml.VgpuPgpuMetadata, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuProcessUtilization(nvml.Device, uint64) (_ []nvml.VgpuProcessUtilizationSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuProcessesUtilizationInfo(nvml.Device) (_ nvml.VgpuProcessesUtilizationInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuSchedulerCapabilities(nvml.Device) (_ nvml.VgpuSchedulerCapabilities, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuSchedulerLog(nvml.Device) (_ nvml.VgpuSchedulerLog, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuSchedulerState(nvml.Device) (_ nvml.VgpuSchedulerGetState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuTypeCreatablePlacements(nvml.Device, nvml.VgpuTypeId) (_ nvml.VgpuPlacementList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuTypeSupportedPlacements(nvml.Device, nvml.VgpuTypeId) (_ nvml.VgpuPlacementList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVgpuUtilization(nvml.Device, uint64) (_ nvml.ValueType, _ []nvml.VgpuInstanceUtilizationSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetViolationStatus(nvml.Device, nvml.PerfPolicyType) (_ nvml.ViolationTime, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceGetVirtualizationMode(nvml.Device) (_ nvml.GpuVirtualizationMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceIsMigDeviceHandle(nvml.Device) (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceModifyDrainState(*nvml.PciInfo, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceOnSameBoard(nvml.Device, nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceQueryDrainState(*nvml.PciInfo) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceRegisterEvents(nvml.Device, uint64, nvml.EventSet) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceRemoveGpu(*nvml.PciInfo) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceRemoveGpu_v2(*nvml.PciInfo, nvml.DetachGpuState, nvml.PcieLinkState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceResetApplicationsClocks(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceResetGpuLockedClocks(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceResetMemoryLockedClocks(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceResetNvLinkErrorCounters(nvml.Device, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceResetNvLinkUtilizationCounter(nvml.Device, int, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetAPIRestriction(nvml.Device, nvml.RestrictedAPI, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetAccountingMode(nvml.Device, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetApplicationsClocks(nvml.Device, uint32, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetAutoBoostedClocksEnabled(nvml.Device, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetComputeMode(nvml.Device, nvml.ComputeMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetConfComputeUnprotectedMemSize(nvml.Device, uint64) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetCpuAffinity(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetDefaultAutoBoostedClocksEnabled(nvml.Device, nvml.EnableState, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetDefaultFanSpeed_v2(nvml.Device, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetDriverModel(nvml.Device, nvml.DriverModel, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetEccMode(nvml.Device, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetFanControlPolicy(nvml.Device, int, nvml.FanControlPolicy) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetFanSpeed_v2(nvml.Device, int, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetGpcClkVfOffset(nvml.Device, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetGpuLockedClocks(nvml.Device, uint32, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetGpuOperationMode(nvml.Device, nvml.GpuOperationMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetMemClkVfOffset(nvml.Device, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetMemoryLockedClocks(nvml.Device, uint32, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetMigMode(nvml.Device, int) (_ nvml.Return, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetNvLinkDeviceLowPowerThreshold(nvml.Device, *nvml.NvLinkPowerThres) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetNvLinkUtilizationControl(nvml.Device, int, int, *nvml.NvLinkUtilizationControl, bool) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetPersistenceMode(nvml.Device, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetPowerManagementLimit(nvml.Device, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetPowerManagementLimit_v2(nvml.Device, *nvml.PowerValue_v2) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetTemperatureThreshold(nvml.Device, nvml.TemperatureThresholds, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetVgpuCapabilities(nvml.Device, nvml.DeviceVgpuCapability, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetVgpuHeterogeneousMode(nvml.Device, nvml.VgpuHeterogeneousMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetVgpuSchedulerState(nvml.Device, *nvml.VgpuSchedulerSetState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceSetVirtualizationMode(nvml.Device, nvml.GpuVirtualizationMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) DeviceValidateInforom(nvml.Device) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) ErrorString(nvml.Return) (_ string) {
+	return
+}
+
+func (unsupportedInterface) EventSetCreate() (_ nvml.EventSet, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) EventSetFree(nvml.EventSet) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) EventSetWait(nvml.EventSet, uint32) (_ nvml.EventData, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) Extensions() (_ nvml.ExtendedInterface) {
+	return
+}
+
+func (unsupportedInterface) GetExcludedDeviceCount() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GetExcludedDeviceInfoByIndex(int) (_ nvml.ExcludedDeviceInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GetVgpuCompatibility(*nvml.VgpuMetadata, *nvml.VgpuPgpuMetadata) (_ nvml.VgpuPgpuCompatibility, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GetVgpuDriverCapabilities(nvml.VgpuDriverCapability) (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GetVgpuVersion() (_ nvml.VgpuVersion, _ nvml.VgpuVersion, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmMetricsGet(*nvml.GpmMetricsGetType) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmMetricsGetV(*nvml.GpmMetricsGetType) (_ nvml.GpmMetricsGetVType) {
+	return
+}
+
+func (unsupportedInterface) GpmMigSampleGet(nvml.Device, int, nvml.GpmSample) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmQueryDeviceSupport(nvml.Device) (_ nvml.GpmSupport, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmQueryDeviceSupportV(nvml.Device) (_ nvml.GpmSupportV) {
+	return
+}
+
+func (unsupportedInterface) GpmQueryIfStreamingEnabled(nvml.Device) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmSampleAlloc() (_ nvml.GpmSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmSampleFree(nvml.GpmSample) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmSampleGet(nvml.Device, nvml.GpmSample) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpmSetStreamingEnabled(nvml.Device, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceCreateComputeInstance(nvml.GpuInstance, *nvml.ComputeInstanceProfileInfo) (_ nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceCreateComputeInstanceWithPlacement(nvml.GpuInstance, *nvml.ComputeInstanceProfileInfo, *nvml.ComputeInstancePlacement) (_ nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceDestroy(nvml.GpuInstance) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetComputeInstanceById(nvml.GpuInstance, int) (_ nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetComputeInstancePossiblePlacements(nvml.GpuInstance, *nvml.ComputeInstanceProfileInfo) (_ []nvml.ComputeInstancePlacement, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetComputeInstanceProfileInfo(nvml.GpuInstance, int, int) (_ nvml.ComputeInstanceProfileInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetComputeInstanceProfileInfoV(nvml.GpuInstance, int, int) (_ nvml.ComputeInstanceProfileInfoHandler) {
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetComputeInstanceRemainingCapacity(nvml.GpuInstance, *nvml.ComputeInstanceProfileInfo) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetComputeInstances(nvml.GpuInstance, *nvml.ComputeInstanceProfileInfo) (_ []nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) GpuInstanceGetInfo(nvml.GpuInstance) (_ nvml.GpuInstanceInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) Init() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) InitWithFlags(uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SetVgpuVersion(*nvml.VgpuVersion) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) Shutdown() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetConfComputeCapabilities() (_ nvml.ConfComputeSystemCaps, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetConfComputeKeyRotationThresholdInfo() (_ nvml.ConfComputeGetKeyRotationThresholdInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetConfComputeSettings() (_ nvml.SystemConfComputeSettings, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetCudaDriverVersion() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetCudaDriverVersion_v2() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetDriverVersion() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetHicVersion() (_ []nvml.HwbcEntry, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetNVMLVersion() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetProcessName(int) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemGetTopologyGpuSet(int) (_ []nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) SystemSetConfComputeKeyRotationThresholdInfo(nvml.ConfComputeSetKeyRotationThresholdInfo) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetCount() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetDevices(nvml.Unit) (_ []nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetFanSpeedInfo(nvml.Unit) (_ nvml.UnitFanSpeeds, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetHandleByIndex(int) (_ nvml.Unit, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetLedState(nvml.Unit) (_ nvml.LedState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetPsuInfo(nvml.Unit) (_ nvml.PSUInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetTemperature(nvml.Unit, int) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitGetUnitInfo(nvml.Unit) (_ nvml.UnitInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) UnitSetLedState(nvml.Unit, nvml.LedColor) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceClearAccountingPids(nvml.VgpuInstance) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetAccountingMode(nvml.VgpuInstance) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetAccountingPids(nvml.VgpuInstance) (_ []int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetAccountingStats(nvml.VgpuInstance, int) (_ nvml.AccountingStats, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetEccMode(nvml.VgpuInstance) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetEncoderCapacity(nvml.VgpuInstance) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetEncoderSessions(nvml.VgpuInstance) (_ int, _ nvml.EncoderSessionInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetEncoderStats(nvml.VgpuInstance) (_ int, _ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetFBCSessions(nvml.VgpuInstance) (_ int, _ nvml.FBCSessionInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetFBCStats(nvml.VgpuInstance) (_ nvml.FBCStats, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetFbUsage(nvml.VgpuInstance) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetFrameRateLimit(nvml.VgpuInstance) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetGpuInstanceId(nvml.VgpuInstance) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetGpuPciId(nvml.VgpuInstance) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetLicenseInfo(nvml.VgpuInstance) (_ nvml.VgpuLicenseInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetLicenseStatus(nvml.VgpuInstance) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetMdevUUID(nvml.VgpuInstance) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetMetadata(nvml.VgpuInstance) (_ nvml.VgpuMetadata, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetType(nvml.VgpuInstance) (_ nvml.VgpuTypeId, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetUUID(nvml.VgpuInstance) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetVmDriverVersion(nvml.VgpuInstance) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceGetVmID(nvml.VgpuInstance) (_ string, _ nvml.VgpuVmIdType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuInstanceSetEncoderCapacity(nvml.VgpuInstance, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetCapabilities(nvml.VgpuTypeId, nvml.VgpuCapability) (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetClass(nvml.VgpuTypeId) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetDeviceID(nvml.VgpuTypeId) (_ uint64, _ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetFrameRateLimit(nvml.VgpuTypeId) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetFramebufferSize(nvml.VgpuTypeId) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetGpuInstanceProfileId(nvml.VgpuTypeId) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetLicense(nvml.VgpuTypeId) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetMaxInstances(nvml.Device, nvml.VgpuTypeId) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetMaxInstancesPerVm(nvml.VgpuTypeId) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetName(nvml.VgpuTypeId) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetNumDisplayHeads(nvml.VgpuTypeId) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedInterface) VgpuTypeGetResolution(nvml.VgpuTypeId, int) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+// unsupportedDevice implements nvml.Device, every call returns ERROR_NOT_SUPPORTED
+type unsupportedDevice struct{}
+
+func (unsupportedDevice) ClearAccountingPids() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ClearCpuAffinity() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ClearEccErrorCounts(nvml.EccCounterType) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ClearFieldValues([]nvml.FieldValue) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) CreateGpuInstance(*nvml.GpuInstanceProfileInfo) (_ nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) CreateGpuInstanceWithPlacement(*nvml.GpuInstanceProfileInfo, *nvml.GpuInstancePlacement) (_ nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) FreezeNvLinkUtilizationCounter(int, int, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAPIRestriction(nvml.RestrictedAPI) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAccountingBufferSize() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAccountingMode() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAccountingPids() (_ []int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAccountingStats(uint32) (_ nvml.AccountingStats, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetActiveVgpus() (_ []nvml.VgpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAdaptiveClockInfoStatus() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetApplicationsClock(nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetArchitecture() (_ nvml.DeviceArchitecture, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAttributes() (_ nvml.DeviceAttributes, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetAutoBoostedClocksEnabled() (_ nvml.EnableState, _ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetBAR1MemoryInfo() (_ nvml.BAR1Memory, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetBoardId() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetBoardPartNumber() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetBrand() (_ nvml.BrandType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetBridgeChipInfo() (_ nvml.BridgeChipHierarchy, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetBusType() (_ nvml.BusType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetC2cModeInfoV() (_ nvml.C2cModeInfoHandler) {
+	return
+}
+
+func (unsupportedDevice) GetClkMonStatus() (_ nvml.ClkMonStatus, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetClock(nvml.ClockType, nvml.ClockId) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetClockInfo(nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetComputeInstanceId() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetComputeMode() (_ nvml.ComputeMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetComputeRunningProcesses() (_ []nvml.ProcessInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetConfComputeGpuAttestationReport() (_ nvml.ConfComputeGpuAttestationReport, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetConfComputeGpuCertificate() (_ nvml.ConfComputeGpuCertificate, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetConfComputeMemSizeInfo() (_ nvml.ConfComputeMemSizeInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetConfComputeProtectedMemoryUsage() (_ nvml.Memory, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCpuAffinity(int) (_ []uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCpuAffinityWithinScope(int, nvml.AffinityScope) (_ []uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCreatableVgpus() (_ []nvml.VgpuTypeId, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCudaComputeCapability() (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCurrPcieLinkGeneration() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCurrPcieLinkWidth() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCurrentClocksEventReasons() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetCurrentClocksThrottleReasons() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDecoderUtilization() (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDefaultApplicationsClock(nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDefaultEccMode() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDetailedEccErrors(nvml.MemoryErrorType, nvml.EccCounterType) (_ nvml.EccErrorCounts, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDeviceHandleFromMigDeviceHandle() (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDisplayActive() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDisplayMode() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDriverModel() (_ nvml.DriverModel, _ nvml.DriverModel, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetDynamicPstatesInfo() (_ nvml.GpuDynamicPstatesInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetEccMode() (_ nvml.EnableState, _ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetEncoderCapacity(nvml.EncoderType) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetEncoderSessions() (_ []nvml.EncoderSessionInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetEncoderStats() (_ int, _ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetEncoderUtilization() (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetEnforcedPowerLimit() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetFBCSessions() (_ []nvml.FBCSessionInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetFBCStats() (_ nvml.FBCStats, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetFanControlPolicy_v2(int) (_ nvml.FanControlPolicy, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetFanSpeed() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetFanSpeed_v2(int) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetFieldValues([]nvml.FieldValue) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpcClkMinMaxVfOffset() (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpcClkVfOffset() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuFabricInfo() (_ nvml.GpuFabricInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuFabricInfoV() (_ nvml.GpuFabricInfoHandler) {
+	return
+}
+
+func (unsupportedDevice) GetGpuInstanceById(int) (_ nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuInstanceId() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuInstancePossiblePlacements(*nvml.GpuInstanceProfileInfo) (_ []nvml.GpuInstancePlacement, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuInstanceProfileInfo(int) (_ nvml.GpuInstanceProfileInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuInstanceProfileInfoV(int) (_ nvml.GpuInstanceProfileInfoHandler) {
+	return
+}
+
+func (unsupportedDevice) GetGpuInstanceRemainingCapacity(*nvml.GpuInstanceProfileInfo) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuInstances(*nvml.GpuInstanceProfileInfo) (_ []nvml.GpuInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuMaxPcieLinkGeneration() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGpuOperationMode() (_ nvml.GpuOperationMode, _ nvml.GpuOperationMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGraphicsRunningProcesses() (_ []nvml.ProcessInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGridLicensableFeatures() (_ nvml.GridLicensableFeatures, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGspFirmwareMode() (_ bool, _ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetGspFirmwareVersion() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetHostVgpuMode() (_ nvml.HostVgpuMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetIndex() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetInforomConfigurationChecksum() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetInforomImageVersion() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetInforomVersion(nvml.InforomObject) (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetIrqNum() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetJpgUtilization() (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetLastBBXFlushTime() (_ uint64, _ uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMPSComputeRunningProcesses() (_ []nvml.ProcessInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMaxClockInfo(nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMaxCustomerBoostClock(nvml.ClockType) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMaxMigDeviceCount() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMaxPcieLinkGeneration() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMaxPcieLinkWidth() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemClkMinMaxVfOffset() (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemClkVfOffset() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemoryAffinity(int, nvml.AffinityScope) (_ []uint, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemoryBusWidth() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemoryErrorCounter(nvml.MemoryErrorType, nvml.EccCounterType, nvml.MemoryLocation) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemoryInfo() (_ nvml.Memory, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMemoryInfo_v2() (_ nvml.Memory_v2, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMigDeviceHandleByIndex(int) (_ nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMigMode() (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMinMaxClockOfPState(nvml.ClockType, nvml.Pstates) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMinMaxFanSpeed() (_ int, _ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMinorNumber() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetModuleId() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetMultiGpuBoard() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetName() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNumFans() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNumGpuCores() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNumaNodeId() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkCapability(int, nvml.NvLinkCapability) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkErrorCounter(int, nvml.NvLinkErrorCounter) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkRemoteDeviceType(int) (_ nvml.IntNvLinkDeviceType, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkRemotePciInfo(int) (_ nvml.PciInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkState(int) (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkUtilizationControl(int, int) (_ nvml.NvLinkUtilizationControl, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkUtilizationCounter(int, int) (_ uint64, _ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetNvLinkVersion(int) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetOfaUtilization() (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetP2PStatus(nvml.Device, nvml.GpuP2PCapsIndex) (_ nvml.GpuP2PStatus, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPciInfo() (_ nvml.PciInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPciInfoExt() (_ nvml.PciInfoExt, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPcieLinkMaxSpeed() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPcieReplayCounter() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPcieSpeed() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPcieThroughput(nvml.PcieUtilCounter) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPerformanceState() (_ nvml.Pstates, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPersistenceMode() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPgpuMetadataString() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerManagementDefaultLimit() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerManagementLimit() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerManagementLimitConstraints() (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerManagementMode() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerSource() (_ nvml.PowerSource, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerState() (_ nvml.Pstates, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetPowerUsage() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetProcessUtilization(uint64) (_ []nvml.ProcessUtilizationSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetProcessesUtilizationInfo() (_ nvml.ProcessesUtilizationInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetRemappedRows() (_ int, _ int, _ bool, _ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetRetiredPages(nvml.PageRetirementCause) (_ []uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetRetiredPagesPendingStatus() (_ nvml.EnableState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetRetiredPages_v2(nvml.PageRetirementCause) (_ []uint64, _ []uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetRowRemapperHistogram() (_ nvml.RowRemapperHistogramValues, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetRunningProcessDetailList() (_ nvml.ProcessDetailList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSamples(nvml.SamplingType, uint64) (_ nvml.ValueType, _ []nvml.Sample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSerial() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSramEccErrorStatus() (_ nvml.EccSramErrorStatus, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedClocksEventReasons() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedClocksThrottleReasons() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedEventTypes() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedGraphicsClocks(int) (_ int, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedMemoryClocks() (_ int, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedPerformanceStates() (_ []nvml.Pstates, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetSupportedVgpus() (_ []nvml.VgpuTypeId, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTargetFanSpeed(int) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTemperature(nvml.TemperatureSensors) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTemperatureThreshold(nvml.TemperatureThresholds) (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetThermalSettings(uint32) (_ nvml.GpuThermalSettings, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTopologyCommonAncestor(nvml.Device) (_ nvml.GpuTopologyLevel, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTopologyNearestGpus(nvml.GpuTopologyLevel) (_ []nvml.Device, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTotalEccErrors(nvml.MemoryErrorType, nvml.EccCounterType) (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetTotalEnergyConsumption() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetUUID() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetUtilizationRates() (_ nvml.Utilization, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVbiosVersion() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuCapabilities(nvml.DeviceVgpuCapability) (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuHeterogeneousMode() (_ nvml.VgpuHeterogeneousMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuInstancesUtilizationInfo() (_ nvml.VgpuInstancesUtilizationInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuMetadata() (_ nvml.VgpuPgpuMetadata, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuProcessUtilization(uint64) (_ []nvml.VgpuProcessUtilizationSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuProcessesUtilizationInfo() (_ nvml.VgpuProcessesUtilizationInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuSchedulerCapabilities() (_ nvml.VgpuSchedulerCapabilities, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuSchedulerLog() (_ nvml.VgpuSchedulerLog, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuSchedulerState() (_ nvml.VgpuSchedulerGetState, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuTypeCreatablePlacements(nvml.VgpuTypeId) (_ nvml.VgpuPlacementList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuTypeSupportedPlacements(nvml.VgpuTypeId) (_ nvml.VgpuPlacementList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVgpuUtilization(uint64) (_ nvml.ValueType, _ []nvml.VgpuInstanceUtilizationSample, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetViolationStatus(nvml.PerfPolicyType) (_ nvml.ViolationTime, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GetVirtualizationMode() (_ nvml.GpuVirtualizationMode, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GpmMigSampleGet(int, nvml.GpmSample) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GpmQueryDeviceSupport() (_ nvml.GpmSupport, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GpmQueryDeviceSupportV() (_ nvml.GpmSupportV) {
+	return
+}
+
+func (unsupportedDevice) GpmQueryIfStreamingEnabled() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GpmSampleGet(nvml.GpmSample) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) GpmSetStreamingEnabled(uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) IsMigDeviceHandle() (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) OnSameBoard(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) RegisterEvents(uint64, nvml.EventSet) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ResetApplicationsClocks() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ResetGpuLockedClocks() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ResetMemoryLockedClocks() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ResetNvLinkErrorCounters(int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ResetNvLinkUtilizationCounter(int, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetAPIRestriction(nvml.RestrictedAPI, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetAccountingMode(nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetApplicationsClocks(uint32, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetAutoBoostedClocksEnabled(nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetComputeMode(nvml.ComputeMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetConfComputeUnprotectedMemSize(uint64) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetCpuAffinity() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetDefaultAutoBoostedClocksEnabled(nvml.EnableState, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetDefaultFanSpeed_v2(int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetDriverModel(nvml.DriverModel, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetEccMode(nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetFanControlPolicy(int, nvml.FanControlPolicy) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetFanSpeed_v2(int, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetGpcClkVfOffset(int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetGpuLockedClocks(uint32, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetGpuOperationMode(nvml.GpuOperationMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetMemClkVfOffset(int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetMemoryLockedClocks(uint32, uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetMigMode(int) (_ nvml.Return, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetNvLinkDeviceLowPowerThreshold(*nvml.NvLinkPowerThres) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetNvLinkUtilizationControl(int, int, *nvml.NvLinkUtilizationControl, bool) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetPersistenceMode(nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetPowerManagementLimit(uint32) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetPowerManagementLimit_v2(*nvml.PowerValue_v2) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetTemperatureThreshold(nvml.TemperatureThresholds, int) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetVgpuCapabilities(nvml.DeviceVgpuCapability, nvml.EnableState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetVgpuHeterogeneousMode(nvml.VgpuHeterogeneousMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetVgpuSchedulerState(*nvml.VgpuSchedulerSetState) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) SetVirtualizationMode(nvml.GpuVirtualizationMode) (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) ValidateInforom() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedDevice) VgpuTypeGetMaxInstances(nvml.VgpuTypeId) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+// unsupportedGpuInstance implements nvml.GpuInstance, every call returns ERROR_NOT_SUPPORTED
+type unsupportedGpuInstance struct{}
+
+func (unsupportedGpuInstance) CreateComputeInstance(*nvml.ComputeInstanceProfileInfo) (_ nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) CreateComputeInstanceWithPlacement(*nvml.ComputeInstanceProfileInfo, *nvml.ComputeInstancePlacement) (_ nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) Destroy() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) GetComputeInstanceById(int) (_ nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) GetComputeInstancePossiblePlacements(*nvml.ComputeInstanceProfileInfo) (_ []nvml.ComputeInstancePlacement, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) GetComputeInstanceProfileInfo(int, int) (_ nvml.ComputeInstanceProfileInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) GetComputeInstanceProfileInfoV(int, int) (_ nvml.ComputeInstanceProfileInfoHandler) {
+	return
+}
+
+func (unsupportedGpuInstance) GetComputeInstanceRemainingCapacity(*nvml.ComputeInstanceProfileInfo) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) GetComputeInstances(*nvml.ComputeInstanceProfileInfo) (_ []nvml.ComputeInstance, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedGpuInstance) GetInfo() (_ nvml.GpuInstanceInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+// unsupportedComputeInstance implements nvml.ComputeInstance, every call returns ERROR_NOT_SUPPORTED
+type unsupportedComputeInstance struct{}
+
+func (unsupportedComputeInstance) Destroy() (ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedComputeInstance) GetInfo() (_ nvml.ComputeInstanceInfo, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+// unsupportedVgpuTypeId implements nvml.VgpuTypeId, every call returns ERROR_NOT_SUPPORTED
+type unsupportedVgpuTypeId struct{}
+
+func (unsupportedVgpuTypeId) GetCapabilities(nvml.VgpuCapability) (_ bool, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetClass() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetCreatablePlacements(nvml.Device) (_ nvml.VgpuPlacementList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetDeviceID() (_ uint64, _ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetFrameRateLimit() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetFramebufferSize() (_ uint64, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetGpuInstanceProfileId() (_ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetLicense() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetMaxInstances(nvml.Device) (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetMaxInstancesPerVm() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetName() (_ string, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetNumDisplayHeads() (_ int, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetResolution(int) (_ uint32, _ uint32, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
+
+func (unsupportedVgpuTypeId) GetSupportedPlacements(nvml.Device) (_ nvml.VgpuPlacementList, ret nvml.Return) {
+	ret = nvml.ERROR_NOT_SUPPORTED
+	return
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// fieldValue decodes a value read with GetFieldValues, returning false if
+// the field has a type that can't be represented
+func fieldValue(v nvml.FieldValue) (float64, bool) {
+	return decodeValue(nvml.ValueType(v.ValueType), v.Value)
+}
+
+// decodeValue decodes an nvmlValue_t union of the given type
+func decodeValue(t nvml.ValueType, value [8]byte) (float64, bool) {
+	switch t {
+	case nvml.VALUE_TYPE_DOUBLE:
+		return math.Float64frombits(binary.NativeEndian.Uint64(value[:])), true
+	case nvml.VALUE_TYPE_UNSIGNED_INT:
+		return float64(binary.NativeEndian.Uint32(value[:])), true
+	case nvml.VALUE_TYPE_UNSIGNED_LONG, nvml.VALUE_TYPE_UNSIGNED_LONG_LONG:
+		return float64(binary.NativeEndian.Uint64(value[:])), true
+	case nvml.VALUE_TYPE_SIGNED_LONG_LONG:
+		return float64(int64(binary.NativeEndian.Uint64(value[:]))), true
+	case nvml.VALUE_TYPE_SIGNED_INT:
+		return float64(int32(binary.NativeEndian.Uint32(value[:]))), true
+	}
+	return 0, false
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// deviceLabelNames are the device attributes which can be used as labels
+var deviceLabelNames = []string{"uuid", "index", "minor", "pci_bus_id", "name"}
+
+// parseDeviceLabels parses the comma separated list given to --device.labels
+func parseDeviceLabels(s string) ([]string, error) {
+	var labels []string
+	for _, label := range strings.Split(s, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" || slices.Contains(labels, label) {
+			continue
+		}
+		if !slices.Contains(deviceLabelNames, label) {
+			return nil, fmt.Errorf("unknown device label %q, expected any of: %s", label, strings.Join(deviceLabelNames, ","))
+		}
+		labels = append(labels, label)
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("at least one device label is required")
+	}
+	return labels, nil
+}
+
+// labelFallbacks remembers the label fallbacks which were logged, so each
+// is logged once instead of on every collection
+var labelFallbacks sync.Map
+
+// checkDeviceLabels sets labelFallback on devices which labels can't tell
+// apart, because an attribute is missing or an earlier device has the same
+// values, and logs a warning the first time. Nil devices are skipped.
+func checkDeviceLabels(devices []*Device, labels []string) {
+	if len(labels) == 0 {
+		return
+	}
+	seen := make(map[string]string)
+	for _, d := range devices {
+		if d == nil {
+			continue
+		}
+		values := d.labelValues(labels)
+		key := strings.Join(values, "\x00")
+		var reason string
+		if i := slices.Index(values, ""); i >= 0 {
+			reason = "has no " + labels[i]
+		} else if other, ok := seen[key]; ok {
+			reason = fmt.Sprintf("has the same %s as GPU %s", strings.Join(labels, ","), other)
+		} else {
+			seen[key] = d.Index
+			continue
+		}
+		d.labelFallback = true
+		if _, logged := labelFallbacks.LoadOrStore(d.UUID+"\x00"+reason, true); !logged {
+			log.Warnf("GPU %s (%s) %s, labelling it with %s instead", d.Index, d.UUID, reason, d.fallbackLabel())
+		}
+	}
+}
+
+// withLabels returns a copy of labels with extra appended
+func withLabels(labels []string, extra ...string) []string {
+	return append(slices.Clip(labels), extra...)
+}
+
+// labelValues returns the values of the named device labels followed by
+// extra. Every device label of a device with labelFallback is its
+// fallbackLabel.
+func (d *Device) labelValues(names []string, extra ...string) []string {
+	if !d.labelFallback {
+		return append(d.attributes(names), extra...)
+	}
+	values := make([]string, 0, len(names)+len(extra))
+	for range names {
+		values = append(values, d.fallbackLabel())
+	}
+	return append(values, extra...)
+}
+
+// fallbackLabel returns the UUID of the device, or its index if even that
+// couldn't be read
+func (d *Device) fallbackLabel() string {
+	if d.UUID == "" {
+		return "index:" + d.Index
+	}
+	return d.UUID
+}
+
+// attributes returns the named device attributes, unlike labelValues
+// without any fallback
+func (d *Device) attributes(names []string) []string {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "uuid":
+			values = append(values, d.UUID)
+		case "index":
+			values = append(values, d.Index)
+		case "minor":
+			values = append(values, d.MinorNumber)
+		case "pci_bus_id":
+			values = append(values, d.PciBusID)
+		case "name":
+			values = append(values, d.Name)
+		}
+	}
+	return values
+}
@@ -0,0 +1,102 @@
+package main
+
+import "testing"
+
+func TestParseDeviceLabels(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "minor", want: []string{"minor"}},
+		{in: "uuid, index,uuid", want: []string{"uuid", "index"}},
+		{in: "serial", wantErr: true},
+		{in: ",", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDeviceLabels(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDeviceLabels(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("parseDeviceLabels(%q) = %q, want %q", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseDeviceLabels(%q) = %q, want %q", tt.in, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDeviceLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		labels  []string
+		want    map[string]float64
+		missing []string
+	}{
+		{
+			name:    "uuid and index",
+			fixture: twoDevices,
+			labels:  []string{"uuid", "index"},
+			want: map[string]float64{
+				`nvidia_temperatures{index="1",uuid="GPU-1"}`:                           44,
+				`nvidia_device_collection_success{index="0",uuid="GPU-0"}`:              1,
+				`nvidia_metric_supported{index="0",metric="temperatures",uuid="GPU-0"}`: 1,
+			},
+		},
+		{
+			name: "missing minor falls back to the UUID",
+			fixture: twoDevices + `
+    returns: {GetMinorNumber: NOT_SUPPORTED}
+`,
+			want: map[string]float64{
+				`nvidia_temperatures{minor="0"}`:     51,
+				`nvidia_temperatures{minor="GPU-1"}`: 44,
+				`nvidia_info{index="1",minor="",name="NVIDIA H100 80GB HBM3",pci_bus_id="",uuid="GPU-1"}`: 1,
+			},
+			missing: []string{`nvidia_temperatures{minor=""}`},
+		},
+		{
+			name: "duplicate values fall back to the UUID",
+			fixture: `
+devices:
+  - {uuid: GPU-0, name: NVIDIA A100, minor: 0, temperature: 51}
+  - {uuid: GPU-1, name: NVIDIA A100, minor: 0, temperature: 44}
+`,
+			want: map[string]float64{
+				`nvidia_temperatures{minor="0"}`:     51,
+				`nvidia_temperatures{minor="GPU-1"}`: 44,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := newTestExporter(t, writeFixture(t, tt.fixture), collectorConfig{DeviceLabels: tt.labels}, collectConfig{})
+			checkSamples(t, scrape(t, exporter), tt.want, tt.missing)
+		})
+	}
+}
+
+func TestDeviceLabelsCheckedOnEveryCollection(t *testing.T) {
+	fixture := writeFixture(t, twoDevices)
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{})
+	checkSamples(t, scrape(t, exporter), map[string]float64{
+		`nvidia_temperatures{minor="1"}`: 44,
+	}, nil)
+
+	// A device that shows up later with the same minor number
+	rewriteFixture(t, fixture, twoDevices+`
+  - {uuid: GPU-2, minor: 1, temperature: 60}
+`)
+	checkSamples(t, scrape(t, exporter), map[string]float64{
+		`nvidia_up{reason=""}`:               1,
+		`nvidia_temperatures{minor="1"}`:     44,
+		`nvidia_temperatures{minor="GPU-2"}`: 60,
+	}, nil)
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"flag"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -11,35 +14,28 @@ import (
 
 const namespace = "nvidia"
 
-var usePerProcess = false
-var stripProcessArgs = false
-var stripProcessPath = false
-var prevProcesses []*Process
-
+// Exporter turns the latest snapshot into metrics, it holds no state of
+// its own so every scrape reflects exactly the devices and processes that
+// were found by the last collection
 type Exporter struct {
-	up                        prometheus.Gauge
-	info                      *prometheus.GaugeVec
-	deviceCount               prometheus.Gauge
-	temperatures              *prometheus.GaugeVec
-	deviceInfo                *prometheus.GaugeVec
-	powerUsage                *prometheus.GaugeVec
-	powerLimit                *prometheus.GaugeVec
-	fanSpeed                  *prometheus.GaugeVec
-	memoryTotal               *prometheus.GaugeVec
-	memoryUsed                *prometheus.GaugeVec
-	utilizationMemory         *prometheus.GaugeVec
-	utilizationGPU            *prometheus.GaugeVec
-	clockCurrentGraphics      *prometheus.GaugeVec
-	clockCurrentMemory        *prometheus.GaugeVec
-	utilizationProcessName    *prometheus.GaugeVec
-	utilizationProcessSMUtil  *prometheus.GaugeVec
-	utilizationProcessMemUtil *prometheus.GaugeVec
-	utilizationProcessEncUtil *prometheus.GaugeVec
-	utilizationProcessDecUtil *prometheus.GaugeVec
-	pcieTxBytes               *prometheus.GaugeVec
-	pcieRxBytes               *prometheus.GaugeVec
-	utilizationDecoder        *prometheus.GaugeVec
-	utilizationEncoder        *prometheus.GaugeVec
+	poller                  *Poller
+	deviceLabels            []string
+	collectors              map[string]Collector
+	up                      *prometheus.Desc
+	reinitializations       *prometheus.Desc
+	nvmlErrors              *prometheus.Desc
+	metricSupported         *prometheus.Desc
+	deviceCollectionSuccess *prometheus.Desc
+	lastCollection          *prometheus.Desc
+	collectionDuration      *prometheus.Desc
+	scrapeCollectorSuccess  *prometheus.Desc
+	scrapeCollectorDuration *prometheus.Desc
+	info                    *prometheus.Desc
+	deviceCount             *prometheus.Desc
+	deviceInfo              *prometheus.Desc
+	// metricNames are the names of the metrics the exporter describes
+	// itself, see checkFieldNames
+	metricNames []string
 }
 
 func main() {
@@ -47,20 +43,65 @@ func main() {
 		level         = flag.String("log.level", "info", "Set the output log level")
 		listenAddress = flag.String("web.listen-address", "0.0.0.0:9401", "Address to listen on for web interface and telemetry.")
 		metricsPath   = flag.String("web.telemetry-path", "/metrics", "Path under which to expose metrics.")
+		backend       = flag.String("nvidia.backend", "nvml", "NVML backend to use, one of: nvml, fake")
+		fakeFixture   = flag.String("nvidia.fake-fixture", "", "YAML or JSON fixture describing the devices simulated by the fake backend")
+		interval      = flag.Duration("collection.interval", 0, "Collect in the background at this interval instead of on every scrape")
+		maxAge        = flag.Duration("collection.max-age", 0, "Report nvidia_up 0 once the background snapshot is older than this, defaults to 3 intervals")
+		deviceTimeout = flag.Duration("collection.device-timeout", 5*time.Second, "How long to wait for a single device before reporting it as failed, 0 waits forever")
+		deviceLabels  = flag.String("device.labels", "minor", "Comma separated device attributes to label per-device metrics with, any of: "+strings.Join(deviceLabelNames, ","))
+		configFile    = flag.String("config.file", "", "YAML file listing the NVML fields exported by the fields collector")
+		perProcess    = flag.Bool("nvidia.per-process", false, "Deprecated, use --collector.process")
+		config        collectConfig
+		collectorCfg  collectorConfig
 	)
-	flag.BoolVar(&usePerProcess, "nvidia.per-process", false, "Export per-process utilization")
-	flag.BoolVar(&stripProcessArgs, "nvidia.strip-process-args", false, "Strip args from process names")
-	flag.BoolVar(&stripProcessPath, "nvidia.strip-process-path", false, "Strip path from process names")
+	flag.BoolVar(&collectorCfg.StripProcessArgs, "nvidia.strip-process-args", false, "Strip args from process names")
+	flag.BoolVar(&collectorCfg.StripProcessPath, "nvidia.strip-process-path", false, "Strip path from process names")
+	flag.BoolVar(&collectorCfg.EncoderSessions, "collector.encoder.sessions", false, "Export the frame rate and latency of every encoder and FBC session")
 	flag.Parse()
 	setLogLevel(*level)
+	config.DeviceTimeout = *deviceTimeout
+
+	if *perProcess {
+		log.Warnln("--nvidia.per-process is deprecated, use --collector.process")
+		*collectorState["process"] = true
+	}
+	if !*collectorState["process"] && (collectorCfg.StripProcessArgs || collectorCfg.StripProcessPath) {
+		log.Fatalln("Stripping args and/or path requires the process collector")
+	}
+	if !*collectorState["encoder"] && collectorCfg.EncoderSessions {
+		log.Fatalln("Exporting encoder sessions requires the encoder collector")
+	}
+
+	labels, err := parseDeviceLabels(*deviceLabels)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *collectorState["process"] && slices.Contains(labels, "name") {
+		log.Fatalln("The name device label clashes with the process name label of the process collector")
+	}
+	collectorCfg.DeviceLabels = labels
+	fileCfg, err := loadConfig(*configFile)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	collectorCfg.Fields = fileCfg.Fields
+	collectors := newCollectors(collectorCfg)
 
-	if !usePerProcess && (stripProcessArgs || stripProcessPath) {
-		log.Fatalln("Stripping args and/or path requires gathering per-process utilization")
+	lib, err := newBackend(*backend, *fakeFixture)
+	if err != nil {
+		log.Fatalln(err)
 	}
 
-	prometheus.MustRegister(NewExporter())
+	poller := NewPoller(NewSession(lib), config, collectors, labels, *interval, *maxAge)
+	poller.Start()
+
+	exporter := NewExporter(poller, labels, collectors)
+	if err := checkFieldNames(fileCfg.Fields, append(collectorMetricNames(collectorCfg), exporter.metricNames...)); err != nil {
+		log.Fatalln(err)
+	}
+	prometheus.MustRegister(exporter)
 
-	http.Handle(*metricsPath, promhttp.Handler())
+	http.Handle(*metricsPath, metricsHandler(exporter))
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
              <head><title>NVML Exporter</title></head>
@@ -76,7 +117,8 @@ func main() {
 		w.Write([]byte("OK"))
 	})
 	log.Infof("Starting HTTP server on %s", *listenAddress)
-	log.Infof("Export per-process utilization? %t", usePerProcess)
+	log.Infof("Using %s backend", *backend)
+	log.Infof("Enabled collectors: %s", strings.Join(collectorNames(collectors), ", "))
 	log.Fatal(http.ListenAndServe(*listenAddress, nil))
 }
 
@@ -95,344 +137,172 @@ func setLogLevel(level string) {
 	}
 }
 
-func NewExporter() *Exporter {
-	return &Exporter{
-		up: prometheus.NewGauge(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "up",
-				Help:      "NVML Metric Collection Operational",
-			},
-		),
-		info: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "driver_info",
-				Help:      "NVML Info",
-			},
-			[]string{"version"},
-		),
-		deviceCount: prometheus.NewGauge(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "device_count",
-				Help:      "Count of found nvidia devices",
-			},
-		),
-		deviceInfo: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "info",
-				Help:      "Info as reported by the device",
-			},
-			[]string{"index", "minor", "uuid", "name"},
-		),
-		temperatures: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "temperatures",
-				Help:      "Temperature as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		powerUsage: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "power_usage",
-				Help:      "Power usage as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		powerLimit: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "power_limit",
-				Help:      "Power limit as reported by the device in mW",
-			},
-			[]string{"minor"},
-		),
-		fanSpeed: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "fanspeed",
-				Help:      "Fan speed as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		memoryTotal: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "memory_total",
-				Help:      "Total memory as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		memoryUsed: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "memory_used",
-				Help:      "Used memory as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		utilizationMemory: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_memory",
-				Help:      "Memory Utilization as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		utilizationGPU: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_gpu",
-				Help:      "GPU utilization as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		clockCurrentGraphics: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "clock_current_graphics",
-				Help:      "Current GPU graphics clock speed as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		clockCurrentMemory: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "clock_current_memory",
-				Help:      "Current GPU memory clock speed as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		utilizationProcessName: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_process_name",
-				Help:      "Process name, if value is 0 the name couldn't be determined",
-			},
-			[]string{"minor", "pid", "name"},
-		),
-		utilizationProcessSMUtil: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_process_smutil",
-				Help:      "Process SM utilization stats averaged over 10s",
-			},
-			[]string{"minor", "pid"},
-		),
-		utilizationProcessMemUtil: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_process_memutil",
-				Help:      "Process memory utilization stats averaged over 10s",
-			},
-			[]string{"minor", "pid"},
-		),
-		utilizationProcessEncUtil: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_process_encutil",
-				Help:      "Process encoder utilization stats averaged over 10s",
-			},
-			[]string{"minor", "pid"},
-		),
-		utilizationProcessDecUtil: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_process_decutil",
-				Help:      "Process decoder utilization stats averaged over 10s",
-			},
-			[]string{"minor", "pid"},
-		),
-		pcieTxBytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "pcie_tx_bytes",
-				Help:      "PCIe TX throughput as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		pcieRxBytes: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "pcie_rx_bytes",
-				Help:      "PCIe RX throughput as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		utilizationDecoder: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_decoder",
-				Help:      "Decoder utilization as reported by the device",
-			},
-			[]string{"minor"},
-		),
-		utilizationEncoder: prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "utilization_encoder",
-				Help:      "Encoder utilization as reported by the device",
-			},
-			[]string{"minor"},
-		),
+// metricsHandler serves every enabled collector, or only the ones named by
+// collect[] query parameters
+func metricsHandler(exporter *Exporter) http.Handler {
+	handler := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		names := r.URL.Query()["collect[]"]
+		if len(names) == 0 {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		collectors, err := filterCollectors(exporter.collectors, names)
+		if err != nil {
+			log.Warnf("Couldn't filter collectors: %s", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filtered := *exporter
+		filtered.collectors = collectors
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(&filtered)
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
+
+func NewExporter(poller *Poller, deviceLabels []string, collectors map[string]Collector) *Exporter {
+	e := &Exporter{
+		poller:       poller,
+		deviceLabels: deviceLabels,
+		collectors:   collectors,
+	}
+	desc := func(subsystem, name, help string, labels []string) *prometheus.Desc {
+		fqName := prometheus.BuildFQName(namespace, subsystem, name)
+		e.metricNames = append(e.metricNames, fqName)
+		return prometheus.NewDesc(fqName, help, labels, nil)
 	}
+	e.up = desc("", "up",
+		"NVML Metric Collection Operational, reason is set to the NVML error when down",
+		[]string{"reason"})
+	e.reinitializations = desc("", "nvml_reinitializations_total",
+		"Number of times NVML was initialized again after a driver error",
+		nil)
+	e.nvmlErrors = desc("", "nvml_errors_total",
+		"Number of failed NVML calls by API and return code",
+		withLabels(deviceLabels, "api", "return"))
+	e.metricSupported = desc("", "metric_supported",
+		"Whether the NVML call behind a metric succeeded in the last collection",
+		withLabels(deviceLabels, "metric"))
+	e.deviceCollectionSuccess = desc("", "device_collection_success",
+		"Whether the last collection of the device finished in time",
+		deviceLabels)
+	e.lastCollection = desc("", "last_collection_timestamp_seconds",
+		"Unix timestamp of the last completed collection",
+		nil)
+	e.collectionDuration = desc("", "collection_duration_seconds",
+		"Duration of the last collection",
+		nil)
+	e.scrapeCollectorSuccess = desc("scrape", "collector_success",
+		"Whether a collector succeeded on every device in the last collection",
+		[]string{"collector"})
+	e.scrapeCollectorDuration = desc("scrape", "collector_duration_seconds",
+		"Time a collector spent on all devices in the last collection",
+		[]string{"collector"})
+	e.info = desc("", "driver_info",
+		"NVML Info",
+		[]string{"version"})
+	e.deviceCount = desc("", "device_count",
+		"Count of found nvidia devices",
+		nil)
+	e.deviceInfo = desc("", "info",
+		"Info as reported by the device",
+		deviceLabelNames)
+	return e
 }
 
-// This function is used to check if metric
-// value is valid; we expect nothing less than 0
-// gonvml returns uint data type
-func checkMetric(value float64) bool {
-	if value < 0 {
-		return false
-	} else {
-		return true
+// boolFloat converts b to 1 or 0
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
 	}
+	return 0
 }
 
 func (e *Exporter) Collect(metrics chan<- prometheus.Metric) {
-	data, err := collectMetrics()
-	if err != nil {
-		log.Errorf("Failed to collect metrics: %s", err)
-		e.up.Set(0)
-		e.up.Collect(metrics)
+	data := e.poller.Latest(e.collectors)
+	_, reinitializations := e.poller.session.Status()
+	metrics <- prometheus.MustNewConstMetric(e.reinitializations, prometheus.CounterValue, float64(reinitializations))
+	metrics <- prometheus.MustNewConstMetric(e.lastCollection, prometheus.GaugeValue, float64(data.Timestamp.UnixNano())/1e9)
+	metrics <- prometheus.MustNewConstMetric(e.collectionDuration, prometheus.GaugeValue, data.Duration.Seconds())
+	for _, c := range e.poller.ErrorCounts() {
+		metrics <- prometheus.MustNewConstMetric(e.nvmlErrors, prometheus.CounterValue, float64(c.Count),
+			c.Device.labelValues(e.deviceLabels, c.API, returnName(c.Ret))...)
+	}
+	e.collectScrape(data, metrics)
+
+	if data.Err != nil {
+		metrics <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 0, downReason(data.Err))
+		return
+	}
+	if e.poller.Stale(data) {
+		log.Warnf("Last collection finished %s ago, not exporting stale metrics", time.Since(data.Timestamp).Round(time.Second))
+		metrics <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 0, "stale")
 		return
 	}
 
-	e.up.Set(1)
-	e.info.WithLabelValues(data.Version).Set(1)
-	e.deviceCount.Set(float64(len(data.Devices)))
+	metrics <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 1, "")
+	metrics <- prometheus.MustNewConstMetric(e.info, prometheus.GaugeValue, 1, data.Version)
+	metrics <- prometheus.MustNewConstMetric(e.deviceCount, prometheus.GaugeValue, float64(len(data.Devices)))
 
-	for i := range data.Devices {
-		d := data.Devices[i]
-		e.deviceInfo.WithLabelValues(d.Index, d.MinorNumber, d.Name, d.UUID).Set(1)
-		if checkMetric(d.FanSpeed) {
-			e.fanSpeed.WithLabelValues(d.MinorNumber).Set(d.FanSpeed)
-		}
-		if checkMetric(d.MemoryTotal) {
-			e.memoryTotal.WithLabelValues(d.MinorNumber).Set(d.MemoryTotal)
-		}
-		if checkMetric(d.MemoryUsed) {
-			e.memoryUsed.WithLabelValues(d.MinorNumber).Set(d.MemoryUsed)
-		}
-		if checkMetric(d.PowerUsage) {
-			e.powerUsage.WithLabelValues(d.MinorNumber).Set(d.PowerUsage)
-		}
-		if checkMetric(d.PowerLimit) {
-			e.powerLimit.WithLabelValues(d.MinorNumber).Set(d.PowerLimit)
-		}
-		if checkMetric(d.Temperature) {
-			e.temperatures.WithLabelValues(d.MinorNumber).Set(d.Temperature)
-		}
-		if checkMetric(d.UtilizationGPU) {
-			e.utilizationGPU.WithLabelValues(d.MinorNumber).Set(d.UtilizationGPU)
-		}
-		if checkMetric(d.UtilizationMemory) {
-			e.utilizationMemory.WithLabelValues(d.MinorNumber).Set(d.UtilizationMemory)
-		}
-		if checkMetric(d.ClockCurrentGraphics) {
-			e.clockCurrentGraphics.WithLabelValues(d.MinorNumber).Set(d.ClockCurrentGraphics)
+	for _, d := range data.Devices {
+		labels := d.labelValues(e.deviceLabels)
+		metrics <- prometheus.MustNewConstMetric(e.deviceInfo, prometheus.GaugeValue, 1, d.attributes(deviceLabelNames)...)
+		if !d.CollectionSuccess {
+			metrics <- prometheus.MustNewConstMetric(e.deviceCollectionSuccess, prometheus.GaugeValue, 0, labels...)
+			continue
 		}
-		if checkMetric(d.ClockCurrentMemory) {
-			e.clockCurrentMemory.WithLabelValues(d.MinorNumber).Set(d.ClockCurrentMemory)
-		}
-		// Delete previous process metrics
-		if len(prevProcesses) > 0 {
-			for _, p := range prevProcesses {
-				if p.Name != nil {
-					e.utilizationProcessName.DeleteLabelValues(d.MinorNumber, p.PromPID(), *p.Name)
-				} else {
-					e.utilizationProcessName.DeleteLabelValues(d.MinorNumber, p.PromPID(), "N/A")
-				}
-				e.utilizationProcessSMUtil.DeleteLabelValues(d.MinorNumber, p.PromPID())
-				e.utilizationProcessMemUtil.DeleteLabelValues(d.MinorNumber, p.PromPID())
-				e.utilizationProcessEncUtil.DeleteLabelValues(d.MinorNumber, p.PromPID())
-				e.utilizationProcessDecUtil.DeleteLabelValues(d.MinorNumber, p.PromPID())
-				prevProcesses = nil
+		metrics <- prometheus.MustNewConstMetric(e.deviceCollectionSuccess, prometheus.GaugeValue, 1, labels...)
+		for name, c := range e.collectors {
+			result, ok := d.Results[name]
+			if !ok {
+				continue
 			}
-		}
-		if len(d.UtilizationProcesses) > 0 {
-			for _, p := range d.UtilizationProcesses {
-				if p.Name != nil {
-					e.utilizationProcessName.WithLabelValues(d.MinorNumber, p.PromPID(), *p.Name).Set(1)
-				} else {
-					e.utilizationProcessName.WithLabelValues(d.MinorNumber, p.PromPID(), "N/A").Set(0)
-				}
-				e.utilizationProcessSMUtil.WithLabelValues(d.MinorNumber, p.PromPID()).Set(float64(p.SMUtil))
-				e.utilizationProcessMemUtil.WithLabelValues(d.MinorNumber, p.PromPID()).Set(float64(p.MemUtil))
-				e.utilizationProcessEncUtil.WithLabelValues(d.MinorNumber, p.PromPID()).Set(float64(p.EncUtil))
-				e.utilizationProcessDecUtil.WithLabelValues(d.MinorNumber, p.PromPID()).Set(float64(p.DecUtil))
-				prevProcesses = append(prevProcesses, p)
+			for _, metric := range result.Metrics {
+				metrics <- prometheus.MustNewConstMetric(e.metricSupported, prometheus.GaugeValue, boolFloat(d.Supported[metric]),
+					d.labelValues(e.deviceLabels, metric)...)
 			}
-		}
-		if checkMetric(d.PcieTxBytes) {
-			e.pcieTxBytes.WithLabelValues(d.MinorNumber).Set(d.PcieTxBytes)
-		}
-		if checkMetric(d.PcieRxBytes) {
-			e.pcieRxBytes.WithLabelValues(d.MinorNumber).Set(d.PcieRxBytes)
-		}
-		if checkMetric(d.UtilizationDecoder) {
-			e.utilizationDecoder.WithLabelValues(d.MinorNumber).Set(d.UtilizationDecoder)
-		}
-		if checkMetric(d.UtilizationEncoder) {
-			e.utilizationEncoder.WithLabelValues(d.MinorNumber).Set(d.UtilizationEncoder)
+			c.Collect(d, metrics)
 		}
 	}
+}
 
-	e.deviceCount.Collect(metrics)
-	e.deviceInfo.Collect(metrics)
-	e.fanSpeed.Collect(metrics)
-	e.info.Collect(metrics)
-	e.memoryTotal.Collect(metrics)
-	e.memoryUsed.Collect(metrics)
-	e.powerUsage.Collect(metrics)
-	e.powerLimit.Collect(metrics)
-	e.temperatures.Collect(metrics)
-	e.up.Collect(metrics)
-	e.utilizationGPU.Collect(metrics)
-	e.utilizationMemory.Collect(metrics)
-	e.clockCurrentGraphics.Collect(metrics)
-	e.clockCurrentMemory.Collect(metrics)
-	if usePerProcess {
-		e.utilizationProcessName.Collect(metrics)
-		e.utilizationProcessSMUtil.Collect(metrics)
-		e.utilizationProcessMemUtil.Collect(metrics)
-		e.utilizationProcessEncUtil.Collect(metrics)
-		e.utilizationProcessDecUtil.Collect(metrics)
+// collectScrape exports the outcome of each collector, a collector only
+// succeeds if it succeeded on every device
+func (e *Exporter) collectScrape(data *Metrics, metrics chan<- prometheus.Metric) {
+	for name := range e.collectors {
+		success := data.Err == nil
+		var duration time.Duration
+		for _, d := range data.Devices {
+			result, ok := d.Results[name]
+			if !d.CollectionSuccess || !ok {
+				success = false
+				continue
+			}
+			if result.Err != nil {
+				success = false
+			}
+			duration += result.Duration
+		}
+		metrics <- prometheus.MustNewConstMetric(e.scrapeCollectorSuccess, prometheus.GaugeValue, boolFloat(success), name)
+		metrics <- prometheus.MustNewConstMetric(e.scrapeCollectorDuration, prometheus.GaugeValue, duration.Seconds(), name)
 	}
-	e.pcieTxBytes.Collect(metrics)
-	e.pcieRxBytes.Collect(metrics)
-	e.utilizationDecoder.Collect(metrics)
-	e.utilizationEncoder.Collect(metrics)
 }
 
 func (e *Exporter) Describe(descs chan<- *prometheus.Desc) {
-	e.deviceCount.Describe(descs)
-	e.deviceInfo.Describe(descs)
-	e.fanSpeed.Describe(descs)
-	e.info.Describe(descs)
-	e.memoryTotal.Describe(descs)
-	e.memoryUsed.Describe(descs)
-	e.powerUsage.Describe(descs)
-	e.powerLimit.Describe(descs)
-	e.temperatures.Describe(descs)
-	e.up.Describe(descs)
-	e.utilizationGPU.Describe(descs)
-	e.utilizationMemory.Describe(descs)
-	e.clockCurrentGraphics.Describe(descs)
-	e.clockCurrentMemory.Describe(descs)
-	if usePerProcess {
-		e.utilizationProcessName.Describe(descs)
-		e.utilizationProcessSMUtil.Describe(descs)
-		e.utilizationProcessMemUtil.Describe(descs)
-		e.utilizationProcessEncUtil.Describe(descs)
-		e.utilizationProcessDecUtil.Describe(descs)
+	descs <- e.up
+	descs <- e.reinitializations
+	descs <- e.nvmlErrors
+	descs <- e.metricSupported
+	descs <- e.deviceCollectionSuccess
+	descs <- e.lastCollection
+	descs <- e.collectionDuration
+	descs <- e.scrapeCollectorSuccess
+	descs <- e.scrapeCollectorDuration
+	descs <- e.info
+	descs <- e.deviceCount
+	descs <- e.deviceInfo
+	for _, c := range e.collectors {
+		c.Describe(descs)
 	}
-	e.pcieTxBytes.Describe(descs)
-	e.pcieRxBytes.Describe(descs)
-	e.utilizationDecoder.Describe(descs)
-	e.utilizationEncoder.Describe(descs)
 }
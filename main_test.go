@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newTestExporter returns an Exporter with every collector, collecting the
+// fixture with the fake backend on every scrape. Device labels default to
+// minor.
+func newTestExporter(t *testing.T, fixture string, cfg collectorConfig, config collectConfig) *Exporter {
+	t.Helper()
+	lib, err := newBackend("fake", fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DeviceLabels == nil {
+		cfg.DeviceLabels = []string{"minor"}
+	}
+	collectors := make(map[string]Collector)
+	for name, factory := range factories {
+		collectors[name] = factory(cfg)
+	}
+	poller := NewPoller(NewSession(lib), config, collectors, cfg.DeviceLabels, 0, 0)
+	return NewExporter(poller, cfg.DeviceLabels, collectors)
+}
+
+// scrape gathers c with a pedantic registry and returns the samples keyed
+// like the text format, e.g. nvidia_temperatures{minor="0"}
+func scrape(t *testing.T, c prometheus.Collector) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+			}
+			sort.Strings(labels)
+			key := family.GetName() + "{" + strings.Join(labels, ",") + "}"
+			if m.GetCounter() != nil {
+				samples[key] = m.GetCounter().GetValue()
+			} else {
+				samples[key] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	return samples
+}
+
+// writeFixture writes a fixture to a temporary file and returns its path
+func writeFixture(t *testing.T, fixture string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixture.yaml")
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// checkSamples fails if a sample in want is missing or has another value,
+// or if a sample in missing is exported
+func checkSamples(t *testing.T, samples map[string]float64, want map[string]float64, missing []string) {
+	t.Helper()
+	for key, want := range want {
+		got, ok := samples[key]
+		if !ok {
+			t.Errorf("%s is missing", key)
+		} else if got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+	for _, key := range missing {
+		if _, ok := samples[key]; ok {
+			t.Errorf("%s is exported", key)
+		}
+	}
+}
+
+func TestExporter(t *testing.T) {
+	exporter := newTestExporter(t, "examples/fake.yaml", collectorConfig{}, collectConfig{})
+	samples := scrape(t, exporter)
+	checkSamples(t, samples, map[string]float64{
+		`nvidia_up{reason=""}`:                                 1,
+		`nvidia_nvml_reinitializations_total{}`:                0,
+		`nvidia_driver_info{version="570.133.07"}`:             1,
+		`nvidia_device_count{}`:                                2,
+		`nvidia_device_collection_success{minor="0"}`:          1,
+		`nvidia_device_collection_success{minor="1"}`:          1,
+		`nvidia_scrape_collector_success{collector="memory"}`:  1,
+		`nvidia_metric_supported{metric="fanspeed",minor="0"}`: 1,
+		`nvidia_metric_supported{metric="fanspeed",minor="1"}`: 0,
+		`nvidia_info{index="1",minor="1",name="NVIDIA H100 80GB HBM3",pci_bus_id="00000000:41:00.0",uuid="GPU-5c9a1e3d-7a4b-4f5e-8d2c-0b1f6e9a3c44"}`: 1,
+	}, nil)
+	if _, ok := samples[`nvidia_last_collection_timestamp_seconds{}`]; !ok {
+		t.Error("nvidia_last_collection_timestamp_seconds is missing")
+	}
+}
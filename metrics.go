@@ -1,18 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	log "github.com/sirupsen/logrus"
 )
 
+// Metrics is a snapshot of a single collection
 type Metrics struct {
-	Version string
-	Devices []*Device
+	Version   string
+	Devices   []*Device
+	Err       error
+	Timestamp time.Time
+	Duration  time.Duration
 }
 
 // Process contains the stats of a process running on the GPU
@@ -43,34 +51,145 @@ func (p Process) PromPID() string {
 }
 
 type Device struct {
-	Index                string
-	MinorNumber          string
-	Name                 string
-	UUID                 string
-	Temperature          float64
-	PowerUsage           float64
-	PowerLimit           float64
-	FanSpeed             float64
-	MemoryTotal          float64
-	MemoryUsed           float64
-	UtilizationMemory    float64
-	UtilizationGPU       float64
-	ClockCurrentGraphics float64
-	ClockCurrentMemory   float64
-	UtilizationProcesses []*Process
-	PcieTxBytes          float64
-	PcieRxBytes          float64
-	UtilizationDecoder   float64
-	UtilizationEncoder   float64
-}
-
-func collectMetrics() (*Metrics, error) {
-	if ret := nvml.Init(); ret != nvml.SUCCESS {
-		return nil, fmt.Errorf("failed to initialize nvml: %v", ret)
-	}
-	defer nvml.Shutdown()
-
-	version, ret := nvml.SystemGetDriverVersion()
+	Index             string
+	MinorNumber       string
+	Name              string
+	UUID              string
+	PciBusID          string
+	CollectionSuccess bool
+	// labelFallback is set when the device labels can't tell the device
+	// apart, see checkDeviceLabels
+	labelFallback bool
+	// Supported records for each metric whether the NVML call behind it succeeded
+	Supported map[string]bool
+	// Errors lists the NVML calls which failed during the collection
+	Errors []callError
+	// Results holds the outcome of every collector run on the device
+	Results map[string]*collectorResult
+	// current is the result of the collector being run, set while updating
+	current *collectorResult
+	// generation is the session generation of the collection, see
+	// Session.Generation
+	generation uint64
+	// utilization caches GetUtilizationRates, see utilizationRates
+	utilization   *nvml.Utilization
+	utilizationOK bool
+	// fieldValues are the fields read for the collectors, see readFields
+	fieldValues map[fieldKey]nvml.FieldValue
+	Temperature float64
+	// TemperatureThresholds holds the thresholds the device has, by name
+	TemperatureThresholds     map[string]float64
+	MemoryTemperature         float64
+	ThermalSensors            []ThermalSensor
+	PowerUsageWatts           float64
+	PowerEnforcedLimitWatts   float64
+	PowerManagementLimitWatts float64
+	PowerDefaultLimitWatts    float64
+	PowerLimitModified        float64
+	PowerLimitMinWatts        float64
+	PowerLimitMaxWatts        float64
+	PowerManagementMode       float64
+	PowerSource               string
+	PowerState                float64
+	EnergyJoules              float64
+	PowerInstantWatts         float64
+	PowerAverageWatts         float64
+	FanSpeed                  float64
+	FanSpeedMin               float64
+	FanSpeedMax               float64
+	Fans                      []*Fan
+	MemoryTotal               float64
+	MemoryUsed                float64
+	UtilizationMemory         float64
+	UtilizationGPU            float64
+	ClockCurrentGraphics      float64
+	ClockCurrentMemory        float64
+	ClockCurrentSM            float64
+	ClockCurrentVideo         float64
+	PerformanceState          float64
+	// PstateUtilization is the utilization of the domains which are present, by name
+	PstateUtilization     map[string]float64
+	Clocks                []ClockValue
+	UtilizationProcesses  []*Process
+	PcieTxBytes           float64
+	PcieRxBytes           float64
+	PcieLinkGen           float64
+	PcieLinkGenMax        float64
+	PcieLinkGenGpuMax     float64
+	PcieLinkWidth         float64
+	PcieLinkWidthMax      float64
+	PcieLinkSpeed         float64
+	PcieLinkSpeedMax      float64
+	PcieReplays           float64
+	PcieLinkDegraded      float64
+	UtilizationDecoder    float64
+	UtilizationEncoder    float64
+	EncoderSessions       float64
+	EncoderAverageFPS     float64
+	EncoderAverageLatency float64
+	// EncoderCapacity is the remaining encoder capacity by codec
+	EncoderCapacity    map[string]float64
+	FBCSessions        float64
+	FBCAverageFPS      float64
+	FBCAverageLatency  float64
+	EncoderSessionList []*EncoderSession
+	FBCSessionList     []*EncoderSession
+	// ClockEventReasons and SupportedClockEventReasons are bitmasks of
+	// nvml.ClocksEventReason* values
+	ClockEventReasons          uint64
+	SupportedClockEventReasons uint64
+	EccModeCurrent             float64
+	EccModePending             float64
+	EccErrors                  []eccErrorCount
+	SramThresholdExceeded      float64
+	// RetiredPages is the number of retired pages by cause
+	RetiredPages            map[string]float64
+	RetiredPagesPending     float64
+	RemappedRowsCorrected   float64
+	RemappedRowsUncorrected float64
+	RemappedRowsPending     float64
+	RemappedRowsFailure     float64
+	RowRemapperHistogram    nvml.RowRemapperHistogramValues
+	NvLinks                 []*NvLink
+	// Violations is the time spent capped in seconds, by policy
+	Violations map[string]float64
+	// Fields are the values of the configured NVML fields
+	Fields         []FieldResult
+	MigMode        float64
+	MigModePending float64
+	MigMaxDevices  float64
+	MigDevices     []*MigDevice
+	// VirtualizationMode is the label value of the virtualization mode
+	VirtualizationMode string
+	Vgpus              []*Vgpu
+	// VgpuGuest is set when the device is a vGPU seen from inside a guest
+	VgpuGuest              bool
+	VgpuGuestDriverVersion string
+	VgpuLicenses           []*VgpuLicense
+	// Gpm holds the GPM metrics indexed like gpmMetrics
+	Gpm    []float64
+	GpmMig []*GpmMig
+}
+
+// collectConfig holds the settings used by collectMetrics
+type collectConfig struct {
+	// DeviceTimeout is how long to wait for a single device, 0 waits forever
+	DeviceTimeout time.Duration
+	// Generation is the generation of the session collectMetrics runs in
+	Generation uint64
+	// Running tracks the device goroutines, see Session.Running
+	Running *sync.WaitGroup
+	// DeviceLabels are the labels checkDeviceLabels checks devices with
+	DeviceLabels []string
+}
+
+// collectMetrics runs collectors on every device in parallel, lib must already be
+// initialized. inflight maps the index of devices whose goroutine hasn't
+// returned yet to their UUID and must be shared between collections. A
+// device that fails is reported like one that timed out, only an error that
+// means the session is lost is returned.
+func collectMetrics(lib nvml.Interface, cfg collectConfig, collectors map[string]Collector, inflight *sync.Map) (*Metrics, error) {
+	version, ret := lib.SystemGetDriverVersion()
 	if ret != nvml.SUCCESS {
 		log.Warnf("Failed to get driver version: %v", ret)
 	}
@@ -79,129 +198,305 @@ func collectMetrics() (*Metrics, error) {
 		Version: version,
 	}
 
-	numDevices, ret := nvml.DeviceGetCount()
+	numDevices, ret := lib.DeviceGetCount()
 	if ret != nvml.SUCCESS {
-		return nil, fmt.Errorf("failed to get device count: %v", ret)
+		return nil, &nvmlError{call: "DeviceGetCount", ret: ret}
 	}
 
+	handles := make([]nvml.Device, numDevices)
+	identities := make([]*Device, numDevices)
+	// present are the UUIDs of the devices found and of those still being
+	// collected, whose state collectors have to keep
+	present := make(map[string]bool)
 	for index := range int(numDevices) {
-		device, ret := nvml.DeviceGetHandleByIndex(index)
-		if ret != nvml.SUCCESS {
+		device, ret := lib.DeviceGetHandleByIndex(index)
+		if isFatal(ret) {
+			return nil, &nvmlError{call: "DeviceGetHandleByIndex", ret: ret}
+		} else if ret != nvml.SUCCESS {
 			log.Errorf("failed to get device handle for GPU %d: %v", index, ret)
 			continue
 		}
 
-		uuid, ret := device.GetUUID()
-		if ret != nvml.SUCCESS {
-			log.Errorf("failed to get device UUID for GPU %d: %v", index, ret)
-			continue
+		identity, err := deviceIdentity(index, device)
+		if err != nil {
+			return nil, err
 		}
+		identity.generation = cfg.Generation
+		handles[index] = device
+		identities[index] = identity
+		present[identity.UUID] = true
+	}
+	checkDeviceLabels(identities, cfg.DeviceLabels)
+	inflight.Range(func(_, uuid any) bool {
+		present[uuid.(string)] = true
+		return true
+	})
+	for _, c := range collectors {
+		if k, ok := c.(deviceStateKeeper); ok {
+			k.Retain(present)
+		}
+	}
 
-		name, ret := device.GetName()
-		if ret != nvml.SUCCESS {
-			log.Errorf("failed to get device name for GPU %d: %v", index, ret)
+	results := make([]chan deviceResult, numDevices)
+	for index, identity := range identities {
+		if identity == nil {
 			continue
 		}
-
-		minorNumber, ret := device.GetMinorNumber()
-		if ret != nvml.SUCCESS {
-			log.Errorf("failed to get device minor number for GPU %d: %v", index, ret)
+		// A device still busy from an earlier collection is most likely hung,
+		// don't pile up more goroutines on it
+		if _, busy := inflight.LoadOrStore(index, identity.UUID); busy {
+			log.Warnf("GPU %d (%s) is still busy from a previous collection", index, identity.UUID)
 			continue
 		}
+		results[index] = make(chan deviceResult, 1)
+		if cfg.Running != nil {
+			cfg.Running.Add(1)
+		}
+		go func(index int, device nvml.Device, d Device, result chan<- deviceResult) {
+			defer inflight.Delete(index)
+			if cfg.Running != nil {
+				defer cfg.Running.Done()
+			}
+			dev, err := collectDevice(lib, collectors, device, d)
+			result <- deviceResult{device: dev, err: err}
+		}(index, handles[index], *identity, results[index])
+	}
 
-		temperature, temperatureErr := device.GetTemperature(nvml.TEMPERATURE_GPU)
-
-		powerUsage, powerUsageErr := device.GetPowerUsage()
-
-		powerLimit, powerLimitErr := device.GetEnforcedPowerLimit()
+	ctx := context.Background()
+	if cfg.DeviceTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.DeviceTimeout)
+		defer cancel()
+	}
+	for index, result := range results {
+		if identities[index] == nil {
+			continue
+		}
+		var r *deviceResult
+		if result != nil {
+			select {
+			case res := <-result:
+				r = &res
+			case <-ctx.Done():
+				// Take a result which arrived at the same time as the deadline
+				select {
+				case res := <-result:
+					r = &res
+				default:
+				}
+			}
+		}
+		if r == nil {
+			log.Warnf("Timed out collecting metrics for GPU %d (%s)", index, identities[index].UUID)
+			metrics.Devices = append(metrics.Devices, identities[index])
+			continue
+		}
+		if r.err != nil {
+			var nerr *nvmlError
+			if errors.As(r.err, &nerr) && isFatal(nerr.ret) {
+				return nil, r.err
+			}
+			log.Errorf("Failed to collect metrics for GPU %d (%s): %s", index, identities[index].UUID, r.err)
+			metrics.Devices = append(metrics.Devices, identities[index])
+			continue
+		}
+		metrics.Devices = append(metrics.Devices, r.device)
+	}
+	return metrics, nil
+}
 
-		fanSpeed, fanSpeedErr := device.GetFanSpeed()
+// deviceResult is sent back by the goroutine collecting a single device
+type deviceResult struct {
+	device *Device
+	err    error
+}
 
-		memoryInfo, memoryInfoErr := device.GetMemoryInfo()
+// deviceIdentity reads the attributes used to label a device. These are
+// cached by the driver and answered even by a GPU that is otherwise stuck,
+// an attribute that can't be read is left empty instead of skipping the device.
+func deviceIdentity(index int, device nvml.Device) (*Device, error) {
+	d := &Device{Index: strconv.Itoa(index)}
 
-		utilizationRates, utilizationRatesErr := device.GetUtilizationRates()
+	uuid, ret := device.GetUUID()
+	if isFatal(ret) {
+		return nil, &nvmlError{call: "GetUUID", ret: ret}
+	} else if ret != nvml.SUCCESS {
+		log.Errorf("failed to get device UUID for GPU %d: %v", index, ret)
+	} else {
+		d.UUID = uuid
+	}
 
-		clockCurrentGraphics, clockCurrentGraphicsErr := device.GetClock(nvml.CLOCK_GRAPHICS, nvml.CLOCK_ID_CURRENT)
+	name, ret := device.GetName()
+	if isFatal(ret) {
+		return nil, &nvmlError{call: "GetName", ret: ret}
+	} else if ret != nvml.SUCCESS {
+		log.Errorf("failed to get device name for GPU %d: %v", index, ret)
+	} else {
+		d.Name = name
+	}
 
-		clockCurrentMemory, clockCurrentMemoryErr := device.GetClock(nvml.CLOCK_MEM, nvml.CLOCK_ID_CURRENT)
+	// MIG devices and GPUs under WSL don't have a minor number
+	minorNumber, ret := device.GetMinorNumber()
+	if isFatal(ret) {
+		return nil, &nvmlError{call: "GetMinorNumber", ret: ret}
+	} else if ret != nvml.SUCCESS {
+		log.Debugf("failed to get device minor number for GPU %d: %v", index, ret)
+	} else {
+		d.MinorNumber = strconv.Itoa(minorNumber)
+	}
 
-		pcieTxBytes, pcieTxBytesErr := device.GetPcieThroughput(nvml.PCIE_UTIL_TX_BYTES)
+	pciInfo, ret := device.GetPciInfo()
+	if isFatal(ret) {
+		return nil, &nvmlError{call: "GetPciInfo", ret: ret}
+	} else if ret != nvml.SUCCESS {
+		log.Debugf("failed to get device PCI info for GPU %d: %v", index, ret)
+	} else {
+		d.PciBusID = pciBusID(pciInfo)
+	}
+	return d, nil
+}
 
-		pcieRxBytes, pcieRxBytesErr := device.GetPcieThroughput(nvml.PCIE_UTIL_RX_BYTES)
+// pciBusID returns the bus ID of a device in the domain:bus:device.function
+// format used by nvidia-smi
+func pciBusID(info nvml.PciInfo) string {
+	var b strings.Builder
+	for _, c := range info.BusId {
+		if c == 0 {
+			break
+		}
+		b.WriteByte(byte(c))
+	}
+	return b.String()
+}
 
-		decUtil, _, decUtilErr := device.GetDecoderUtilization()
+// collectDevice runs every collector on a single device, d holds its identity.
+// It stops at the first call that returns a fatal error or GPU_IS_LOST.
+func collectDevice(lib nvml.Interface, collectors map[string]Collector, device nvml.Device, d Device) (*Device, error) {
+	appendDevice := d
+	appendDevice.CollectionSuccess = true
+	appendDevice.Supported = make(map[string]bool)
+	appendDevice.Results = make(map[string]*collectorResult)
+	if err := appendDevice.readFields(device, collectors); err != nil {
+		return nil, err
+	}
+	// Every result exists up front so a call shared by collectors can be
+	// charged to the same one, see utilizationRates
+	for name := range collectors {
+		appendDevice.Results[name] = &collectorResult{}
+	}
+	for name, c := range collectors {
+		result := appendDevice.Results[name]
+		appendDevice.current = result
+		start := time.Now()
+		err := c.Update(lib, device, &appendDevice)
+		result.Duration = time.Since(start)
+		var nerr *nvmlError
+		// Collectors record failed calls with check instead of returning
+		// them, one that ends the device or the session still stops it
+		if err == nil && errors.As(result.Err, &nerr) && (isFatal(nerr.ret) || isLost(nerr.ret)) {
+			err = result.Err
+		}
+		if err != nil {
+			if errors.As(err, &nerr) && (isFatal(nerr.ret) || isLost(nerr.ret)) {
+				return nil, err
+			}
+			log.Errorf("%s collector failed for GPU %s: %s", name, d.Index, err)
+			result.Err = err
+		}
+	}
+	appendDevice.current = nil
+	appendDevice.fieldValues = nil
+	return &appendDevice, nil
+}
 
-		encUtil, _, encUtilErr := device.GetEncoderUtilization()
+// fieldKey identifies a field value read with GetFieldValues
+type fieldKey struct {
+	id    uint32
+	scope uint32
+}
 
-		var appendDevice = Device{
-			Index:                strconv.Itoa(index),
-			MinorNumber:          strconv.Itoa(int(minorNumber)),
-			Name:                 name,
-			UUID:                 uuid,
-			Temperature:          checkError(temperatureErr, float64(temperature), index, "Temperature"),
-			PowerUsage:           checkError(powerUsageErr, float64(powerUsage), index, "PowerUsage"),
-			PowerLimit:           checkError(powerLimitErr, float64(powerLimit), index, "PowerLimit"),
-			FanSpeed:             checkError(fanSpeedErr, float64(fanSpeed), index, "FanSpeed"),
-			MemoryTotal:          checkError(memoryInfoErr, float64(memoryInfo.Total), index, "MemoryTotal"),
-			MemoryUsed:           checkError(memoryInfoErr, float64(memoryInfo.Used), index, "MemoryUsed"),
-			UtilizationMemory:    checkError(utilizationRatesErr, float64(utilizationRates.Memory), index, "UtilizationMemory"),
-			UtilizationGPU:       checkError(utilizationRatesErr, float64(utilizationRates.Gpu), index, "UtilizationGPU"),
-			ClockCurrentGraphics: checkError(clockCurrentGraphicsErr, float64(clockCurrentGraphics), index, "ClockCurrentGraphics"),
-			ClockCurrentMemory:   checkError(clockCurrentMemoryErr, float64(clockCurrentMemory), index, "ClockCurrentMemory"),
-			PcieTxBytes:          checkError(pcieTxBytesErr, float64(pcieTxBytes), index, "PcieTxBytes"),
-			PcieRxBytes:          checkError(pcieRxBytesErr, float64(pcieRxBytes), index, "PcieRxBytes"),
-			UtilizationDecoder:   checkError(decUtilErr, float64(decUtil), index, "UtilizationDecoder"),
-			UtilizationEncoder:   checkError(encUtilErr, float64(encUtil), index, "UtilizationEncoder"),
-		}
-		// Skip process stats if not requested
-		if !usePerProcess {
-			metrics.Devices = append(metrics.Devices, &appendDevice)
+// readFields reads the fields of every collector implementing fieldReader with
+// one GetFieldValues call. Only a fatal error or GPU_IS_LOST is returned,
+// other failures leave the fields missing.
+func (d *Device) readFields(device nvml.Device, collectors map[string]Collector) error {
+	var values []nvml.FieldValue
+	seen := make(map[fieldKey]bool)
+	for _, c := range collectors {
+		r, ok := c.(fieldReader)
+		if !ok {
 			continue
 		}
-		// Collect per-process stats
-		utilizations, utilizationsErr := device.GetProcessUtilization(10)
-		if utilizationsErr != nvml.SUCCESS {
-			log.Errorf("\tfailed to get process utilization for GPU %d: %v", index, utilizationsErr)
-		} else {
-			log.Debugf("process count: %d", len(utilizations))
-			var pList []*Process
-			for _, sample := range utilizations {
-				var p = Process{
-					PID:     sample.Pid,
-					SMUtil:  sample.SmUtil,
-					MemUtil: sample.MemUtil,
-					EncUtil: sample.EncUtil,
-					DecUtil: sample.DecUtil,
-				}
-
-				name, err := nvml.SystemGetProcessName(int(sample.Pid))
-				if err != nvml.SUCCESS {
-					log.Debugf("\tfailed to get process name for PID %d: %v\n", sample.Pid, err)
-				} else {
-					if stripProcessArgs {
-						name = strings.Split(name, " ")[0]
-					}
-					if stripProcessPath {
-						name = filepath.Base(name)
-					}
-					p.Name = &name
-				}
-				pList = append(pList, &p)
-				log.Debug(p.ToString())
+		for _, f := range r.Fields() {
+			key := fieldKey{id: f.FieldId, scope: f.ScopeId}
+			if !seen[key] {
+				seen[key] = true
+				values = append(values, nvml.FieldValue{FieldId: f.FieldId, ScopeId: f.ScopeId})
 			}
-			appendDevice.UtilizationProcesses = pList
 		}
-		metrics.Devices = append(metrics.Devices, &appendDevice)
 	}
-	return metrics, nil
+	if len(values) == 0 {
+		return nil
+	}
+	ret := device.GetFieldValues(values)
+	if isFatal(ret) || isLost(ret) {
+		return &nvmlError{call: "GetFieldValues", ret: ret}
+	}
+	if !d.check("GetFieldValues", ret) {
+		return nil
+	}
+	d.fieldValues = make(map[fieldKey]nvml.FieldValue, len(values))
+	for _, v := range values {
+		d.fieldValues[fieldKey{id: v.FieldId, scope: v.ScopeId}] = v
+	}
+	return nil
 }
 
-// This function is used to check if error is returned
-// if so set float64 to -1
-func checkError(ret nvml.Return, value float64, index int, metric string) float64 {
-	if ret != nvml.SUCCESS {
-		log.Debugf("Unable to collect metrics for %s for device %d: %s", metric, index, ret)
-		return -1
+// fieldValue returns the value of a field read by readFields and whether it
+// could be read, recording the result for metrics
+func (d *Device) fieldValue(id, scope uint32, metrics ...string) (nvml.FieldValue, bool) {
+	v, ok := d.fieldValues[fieldKey{id: id, scope: scope}]
+	if !ok {
+		// The GetFieldValues call failed and was recorded by readFields
+		d.setSupported(false, metrics...)
+		return v, false
+	}
+	return v, d.check("GetFieldValues", nvml.Return(v.NvmlReturn), metrics...)
+}
+
+// callError is an NVML call which failed while collecting a device
+type callError struct {
+	API string
+	Ret nvml.Return
+}
+
+// check records the result of an NVML call made for the given metrics and
+// returns true if it succeeded
+func (d *Device) check(api string, ret nvml.Return, metrics ...string) bool {
+	ok := ret == nvml.SUCCESS
+	d.setSupported(ok, metrics...)
+	if !ok {
+		log.Debugf("%s failed for device %s: %s", api, d.Index, ret)
+		d.Errors = append(d.Errors, callError{API: api, Ret: ret})
+		// A metric the device doesn't have is not a collector failure, a
+		// fatal error or GPU_IS_LOST replaces an earlier one so it isn't lost
+		if d.current != nil && ret != nvml.ERROR_NOT_SUPPORTED && (d.current.Err == nil || isFatal(ret) || isLost(ret)) {
+			d.current.Err = &nvmlError{call: api, ret: ret}
+		}
+	}
+	return ok
+}
+
+// setSupported records whether metrics are supported, for metrics backed by
+// a call which was already checked
+func (d *Device) setSupported(ok bool, metrics ...string) {
+	for _, metric := range metrics {
+		d.Supported[metric] = ok
+	}
+	if d.current != nil {
+		for _, metric := range metrics {
+			if !slices.Contains(d.current.Metrics, metric) {
+				d.current.Metrics = append(d.current.Metrics, metric)
+			}
+		}
 	}
-	return value
 }
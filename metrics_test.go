@@ -0,0 +1,72 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCollectMetricsDeviceTimeout(t *testing.T) {
+	fixture := writeFixture(t, twoDevices+`
+    delay: 20ms
+`)
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{DeviceTimeout: 10 * time.Millisecond})
+	want := map[string]float64{
+		`nvidia_up{reason=""}`:                        1,
+		`nvidia_device_collection_success{minor="0"}`: 1,
+		`nvidia_device_collection_success{minor="1"}`: 0,
+		`nvidia_temperatures{minor="0"}`:              51,
+		`nvidia_info{index="1",minor="1",name="NVIDIA H100 80GB HBM3",pci_bus_id="",uuid="GPU-1"}`: 1,
+	}
+	missing := []string{`nvidia_temperatures{minor="1"}`}
+	checkSamples(t, scrape(t, exporter), want, missing)
+	// The device is still busy from the first scrape
+	checkSamples(t, scrape(t, exporter), want, missing)
+}
+
+func TestCollectMetricsDeviceError(t *testing.T) {
+	collectors := map[string]Collector{
+		"temperature": newTemperatureCollector(collectorConfig{DeviceLabels: []string{"minor"}}),
+	}
+	tests := []struct {
+		name string
+		ret  string
+		// fatal is set if the error must end the whole collection
+		fatal bool
+	}{
+		{name: "lost", ret: "GPU_IS_LOST"},
+		{name: "uninitialized", ret: "UNINITIALIZED", fatal: true},
+		{name: "driver not loaded", ret: "DRIVER_NOT_LOADED", fatal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib, err := newFakeNvml(writeFixture(t, twoDevices+`
+    returns: {GetTemperature: `+tt.ret+`}
+`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var inflight sync.Map
+			metrics, err := collectMetrics(lib, collectConfig{}, collectors, &inflight)
+			if tt.fatal {
+				if err == nil {
+					t.Fatal("collection succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(metrics.Devices) != 2 {
+				t.Fatalf("collected %d devices, want 2", len(metrics.Devices))
+			}
+			healthy, failed := metrics.Devices[0], metrics.Devices[1]
+			if !healthy.CollectionSuccess || healthy.Temperature != 51 {
+				t.Errorf("healthy device: success %v, temperature %v", healthy.CollectionSuccess, healthy.Temperature)
+			}
+			if failed.CollectionSuccess || failed.UUID != "GPU-1" || failed.MinorNumber != "1" {
+				t.Errorf("failed device: success %v, UUID %q, minor %q", failed.CollectionSuccess, failed.UUID, failed.MinorNumber)
+			}
+		})
+	}
+}
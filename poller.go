@@ -0,0 +1,162 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	log "github.com/sirupsen/logrus"
+)
+
+// Poller collects metrics from NVML and keeps the latest result. With a
+// positive interval collection runs in the background and scrapes only read
+// the latest snapshot, otherwise every scrape collects synchronously.
+type Poller struct {
+	session *Session
+	config  collectConfig
+	// collectors are the enabled collectors, run by background collections
+	collectors map[string]Collector
+	// deviceLabels are the labels error counters are exported with
+	deviceLabels []string
+	interval     time.Duration
+	maxAge       time.Duration
+	latest       atomic.Pointer[Metrics]
+	inflight     sync.Map
+
+	mu     sync.Mutex
+	errors map[errorKey]*errorCount
+}
+
+// errorKey identifies a counter by the device label values it is exported
+// with, so two keys never render the same series
+type errorKey struct {
+	labels string
+	api    string
+	ret    nvml.Return
+}
+
+// errorCount is the number of times an NVML call failed on a device with
+// the same return code
+type errorCount struct {
+	Device *Device
+	API    string
+	Ret    nvml.Return
+	Count  uint64
+}
+
+// NewPoller returns a poller, maxAge is how old a background snapshot may
+// get before it is considered stale, defaulting to three intervals
+func NewPoller(session *Session, config collectConfig, collectors map[string]Collector, deviceLabels []string, interval time.Duration, maxAge time.Duration) *Poller {
+	if maxAge <= 0 {
+		maxAge = 3 * interval
+	}
+	config.DeviceLabels = deviceLabels
+	return &Poller{
+		session:      session,
+		config:       config,
+		collectors:   collectors,
+		interval:     interval,
+		maxAge:       maxAge,
+		deviceLabels: deviceLabels,
+		errors:       make(map[errorKey]*errorCount),
+	}
+}
+
+// Start collects once and then keeps collecting every interval in the
+// background, it does nothing when collecting on scrape
+func (p *Poller) Start() {
+	if p.interval <= 0 {
+		return
+	}
+	p.collect(p.collectors)
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			p.collect(p.collectors)
+		}
+	}()
+}
+
+// Latest returns the most recent snapshot, collecting a new one with the
+// given collectors first when running without a background interval
+func (p *Poller) Latest(collectors map[string]Collector) *Metrics {
+	if p.interval <= 0 {
+		return p.collect(collectors)
+	}
+	return p.latest.Load()
+}
+
+// Stale returns true if m is too old to be exported
+func (p *Poller) Stale(m *Metrics) bool {
+	return p.interval > 0 && time.Since(m.Timestamp) > p.maxAge
+}
+
+// collect runs a single collection and stores the resulting snapshot,
+// which must not be modified afterwards
+func (p *Poller) collect(collectors map[string]Collector) *Metrics {
+	start := time.Now()
+	var data *Metrics
+	err := p.session.Do(func(lib nvml.Interface) error {
+		var err error
+		config := p.config
+		config.Generation = p.session.Generation()
+		config.Running = p.session.Running()
+		data, err = collectMetrics(lib, config, collectors, &p.inflight)
+		return err
+	})
+	if err != nil {
+		log.Errorf("Failed to collect metrics: %s", err)
+		data = &Metrics{Err: err}
+	}
+	data.Timestamp = time.Now()
+	data.Duration = data.Timestamp.Sub(start)
+	p.countErrors(data)
+	p.latest.Store(data)
+	return data
+}
+
+// countErrors adds the failed NVML calls of a collection to the totals.
+// Totals of devices which are no longer found are dropped, a failed
+// collection keeps them all.
+func (p *Poller) countErrors(data *Metrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if data.Err == nil {
+		present := make(map[string]bool, len(data.Devices))
+		for _, d := range data.Devices {
+			present[strings.Join(d.labelValues(p.deviceLabels), "\x00")] = true
+		}
+		for key := range p.errors {
+			if !present[key.labels] {
+				delete(p.errors, key)
+			}
+		}
+	}
+	for _, d := range data.Devices {
+		for _, e := range d.Errors {
+			key := errorKey{labels: strings.Join(d.labelValues(p.deviceLabels), "\x00"), api: e.API, ret: e.Ret}
+			c, ok := p.errors[key]
+			if !ok {
+				c = &errorCount{API: e.API, Ret: e.Ret}
+				p.errors[key] = c
+			}
+			// Keep the latest identity so labels follow the device
+			c.Device = d
+			c.Count++
+		}
+	}
+}
+
+// ErrorCounts returns a copy of the failed NVML call totals
+func (p *Poller) ErrorCounts() []errorCount {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	counts := make([]errorCount, 0, len(p.errors))
+	for _, c := range p.errors {
+		counts = append(counts, *c)
+	}
+	return counts
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newBackgroundExporter returns a test exporter whose poller collected once
+// in the background, the next background collection is an hour away
+func newBackgroundExporter(t *testing.T, fixture string, maxAge time.Duration) *Exporter {
+	t.Helper()
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{})
+	exporter.poller.interval = time.Hour
+	exporter.poller.maxAge = maxAge
+	exporter.poller.Start()
+	return exporter
+}
+
+func TestPollerBackground(t *testing.T) {
+	fixture := writeFixture(t, twoDevices)
+	exporter := newBackgroundExporter(t, fixture, time.Hour)
+	first := scrape(t, exporter)
+	checkSamples(t, first, map[string]float64{
+		`nvidia_up{reason=""}`:           1,
+		`nvidia_temperatures{minor="0"}`: 51,
+	}, nil)
+	if _, ok := first[`nvidia_collection_duration_seconds{}`]; !ok {
+		t.Error("nvidia_collection_duration_seconds is missing")
+	}
+
+	// Scrapes only read the snapshot of the last collection
+	rewriteFixture(t, fixture, twoDevices+`
+returns: {DeviceGetCount: DRIVER_NOT_LOADED}
+`)
+	second := scrape(t, exporter)
+	checkSamples(t, second, map[string]float64{
+		`nvidia_up{reason=""}`:                       1,
+		`nvidia_temperatures{minor="0"}`:             51,
+		`nvidia_last_collection_timestamp_seconds{}`: first[`nvidia_last_collection_timestamp_seconds{}`],
+	}, nil)
+}
+
+func TestPollerStale(t *testing.T) {
+	exporter := newBackgroundExporter(t, writeFixture(t, twoDevices), time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	samples := scrape(t, exporter)
+	checkSamples(t, samples, map[string]float64{
+		`nvidia_up{reason="stale"}`: 0,
+	}, []string{
+		`nvidia_up{reason=""}`,
+		`nvidia_temperatures{minor="0"}`,
+		`nvidia_device_collection_success{minor="0"}`,
+	})
+	if _, ok := samples[`nvidia_last_collection_timestamp_seconds{}`]; !ok {
+		t.Error("nvidia_last_collection_timestamp_seconds is missing")
+	}
+}
+
+func TestPollerErrorCounts(t *testing.T) {
+	fixture := writeFixture(t, twoDevices+`
+    returns: {GetFanSpeed: UNKNOWN}
+`)
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{})
+	scrape(t, exporter)
+	checkSamples(t, scrape(t, exporter), map[string]float64{
+		`nvidia_nvml_errors_total{api="GetFanSpeed",minor="1",return="unknown"}`: 2,
+		`nvidia_metric_supported{metric="fanspeed",minor="0"}`:                   1,
+		`nvidia_metric_supported{metric="fanspeed",minor="1"}`:                   0,
+		`nvidia_scrape_collector_success{collector="fan"}`:                       0,
+	}, []string{`nvidia_nvml_errors_total{api="GetFanSpeed",minor="0",return="unknown"}`})
+
+	// The counters of a device go away with it
+	rewriteFixture(t, fixture, `
+devices:
+  - {uuid: GPU-0, minor: 0}
+`)
+	checkSamples(t, scrape(t, exporter), nil, []string{
+		`nvidia_nvml_errors_total{api="GetFanSpeed",minor="1",return="unknown"}`,
+	})
+}
+
+func TestPollerUtilizationRatesCharge(t *testing.T) {
+	fixture := writeFixture(t, `
+devices:
+  - uuid: GPU-0
+    pcie_link_gen: 4
+    pcie_link_gen_max: 4
+    pcie_link_width: 16
+    pcie_link_width_max: 16
+    pcie_link_max_speed: 32000
+    returns: {GetUtilizationRates: UNKNOWN}
+`)
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{})
+	// Collectors run in random order, the failure must always be charged to
+	// the utilization collector
+	for range 20 {
+		checkSamples(t, scrape(t, exporter), map[string]float64{
+			`nvidia_scrape_collector_success{collector="utilization"}`:       0,
+			`nvidia_scrape_collector_success{collector="pcie"}`:              1,
+			`nvidia_metric_supported{metric="pcie_link_degraded",minor="0"}`: 0,
+		}, nil)
+	}
+}
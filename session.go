@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	minReinitBackoff = time.Second
+	maxReinitBackoff = time.Minute
+)
+
+// nvmlError is returned when an NVML call fails in a way that aborts collection
+type nvmlError struct {
+	call string
+	ret  nvml.Return
+}
+
+func (e *nvmlError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.call, e.ret)
+}
+
+// isFatal returns true if ret means the NVML session is no longer usable
+// and has to be initialized again
+func isFatal(ret nvml.Return) bool {
+	switch ret {
+	case nvml.ERROR_UNINITIALIZED, nvml.ERROR_DRIVER_NOT_LOADED:
+		return true
+	}
+	return false
+}
+
+// isLost returns true if ret means the GPU fell off the bus. From a device
+// call only that device failed, from a library-level call the session is lost.
+func isLost(ret nvml.Return) bool {
+	return ret == nvml.ERROR_GPU_IS_LOST
+}
+
+// Session keeps NVML initialized for the life of the process. When a call
+// fails with a fatal error the library is shut down and initialized again,
+// backing off exponentially while initialization keeps failing.
+type Session struct {
+	lib nvml.Interface
+
+	// mu is held for the whole of Do, it guards the initialization state
+	mu              sync.Mutex
+	initialized     bool
+	everInitialized bool
+	backoff         time.Duration
+	nextAttempt     time.Time
+	// running are the goroutines still using the current session
+	running *sync.WaitGroup
+
+	// err and reinitializations are read by Status, they are atomic so a
+	// scrape never waits for a collection in progress
+	err               atomic.Pointer[nvmlError]
+	reinitializations atomic.Uint64
+}
+
+func NewSession(lib nvml.Interface) *Session {
+	return &Session{lib: lib, running: new(sync.WaitGroup)}
+}
+
+// Do runs fn with an initialized NVML library. If NVML is down and the
+// backoff has not expired yet, fn is not called and an error is returned.
+// Errors of device calls should be handled by fn, an error it returns is
+// taken to come from a library-level call.
+func (s *Session) Do(fn func(lib nvml.Interface) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.init(); err != nil {
+		return err
+	}
+	err := fn(s.lib)
+	var nerr *nvmlError
+	if !errors.As(err, &nerr) || !(isFatal(nerr.ret) || isLost(nerr.ret)) {
+		s.backoff = 0
+		return err
+	}
+	log.Warnf("NVML session lost, will re-initialize: %s", err)
+	s.initialized = false
+	s.down(nerr)
+	s.nextAttempt = time.Now().Add(s.backoff)
+	// Devices which timed out may still be calling into NVML, shut it down
+	// once they return. Init and Shutdown are reference counted, so the next
+	// session can start in the meantime.
+	running := s.running
+	s.running = new(sync.WaitGroup)
+	go func() {
+		running.Wait()
+		s.lib.Shutdown()
+	}()
+	return err
+}
+
+// init initializes NVML if it isn't already, must be called with s.mu held
+func (s *Session) init() error {
+	if s.initialized {
+		return nil
+	}
+	if time.Now().Before(s.nextAttempt) {
+		return fmt.Errorf("NVML is down, next attempt in %s: %w", time.Until(s.nextAttempt).Round(time.Second), s.err.Load())
+	}
+	if ret := s.lib.Init(); ret != nvml.SUCCESS {
+		err := &nvmlError{call: "Init", ret: ret}
+		s.down(err)
+		s.nextAttempt = time.Now().Add(s.backoff)
+		return err
+	}
+	if s.everInitialized {
+		s.reinitializations.Add(1)
+		log.Infof("NVML re-initialized after %s", s.err.Load())
+	}
+	s.initialized = true
+	s.everInitialized = true
+	s.err.Store(nil)
+	return nil
+}
+
+// down records why NVML is unusable and increases the backoff
+func (s *Session) down(err *nvmlError) {
+	s.err.Store(err)
+	switch {
+	case s.backoff == 0:
+		s.backoff = minReinitBackoff
+	case s.backoff < maxReinitBackoff:
+		s.backoff = min(s.backoff*2, maxReinitBackoff)
+	}
+}
+
+// Status returns the reason NVML is down, or an empty string if it's up,
+// and the number of times it was re-initialized
+func (s *Session) Status() (string, uint64) {
+	reinitializations := s.reinitializations.Load()
+	err := s.err.Load()
+	if err == nil {
+		return "", reinitializations
+	}
+	return returnName(err.ret), reinitializations
+}
+
+// Running returns the WaitGroup goroutines started by Do have to be added
+// to if they keep using NVML after Do returns, NVML isn't shut down before
+// they are done. It must only be called from within Do.
+func (s *Session) Running() *sync.WaitGroup {
+	return s.running
+}
+
+// Generation returns a number which changes every time NVML is initialized
+// again, state kept from an earlier generation is no longer valid
+func (s *Session) Generation() uint64 {
+	return s.reinitializations.Load()
+}
+
+// downReason returns the value of the nvidia_up reason label for a failed collection
+func downReason(err error) string {
+	var nerr *nvmlError
+	if errors.As(err, &nerr) {
+		return returnName(nerr.ret)
+	}
+	return "error"
+}
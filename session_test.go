@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+const twoDevices = `
+driver_version: "570.133.07"
+devices:
+  - uuid: GPU-0
+    name: NVIDIA GeForce RTX 4090
+    minor: 0
+    temperature: 51
+    fan_speed: 30
+  - uuid: GPU-1
+    name: NVIDIA H100 80GB HBM3
+    minor: 1
+    temperature: 44
+    fan_speed: 40
+`
+
+// rewriteFixture replaces a fixture written by writeFixture, moving its
+// modification time forward so the fake backend reloads it
+func rewriteFixture(t *testing.T, path, fixture string) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeviceLost(t *testing.T) {
+	fixture := writeFixture(t, twoDevices+`
+    returns: {GetFanSpeed: GPU_IS_LOST}
+`)
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{})
+	for range 2 {
+		checkSamples(t, scrape(t, exporter), map[string]float64{
+			`nvidia_up{reason=""}`:                        1,
+			`nvidia_nvml_reinitializations_total{}`:       0,
+			`nvidia_device_collection_success{minor="0"}`: 1,
+			`nvidia_device_collection_success{minor="1"}`: 0,
+			`nvidia_fanspeed{minor="0"}`:                  30,
+			`nvidia_temperatures{minor="0"}`:              51,
+		}, []string{
+			`nvidia_fanspeed{minor="1"}`,
+			`nvidia_temperatures{minor="1"}`,
+		})
+	}
+}
+
+func TestSessionLost(t *testing.T) {
+	fixture := writeFixture(t, twoDevices+`
+returns: {DeviceGetCount: DRIVER_NOT_LOADED}
+`)
+	exporter := newTestExporter(t, fixture, collectorConfig{}, collectConfig{})
+	down := map[string]float64{
+		`nvidia_up{reason="driver_not_loaded"}`: 0,
+		`nvidia_nvml_reinitializations_total{}`: 0,
+	}
+	checkSamples(t, scrape(t, exporter), down, nil)
+
+	// NVML isn't initialized again before the backoff expires
+	rewriteFixture(t, fixture, twoDevices)
+	checkSamples(t, scrape(t, exporter), down, nil)
+
+	exporter.poller.session.nextAttempt = time.Time{}
+	checkSamples(t, scrape(t, exporter), map[string]float64{
+		`nvidia_up{reason=""}`:                  1,
+		`nvidia_nvml_reinitializations_total{}`: 1,
+		`nvidia_temperatures{minor="1"}`:        44,
+	}, nil)
+}
+
+// shutdownCounter counts the calls to Shutdown
+type shutdownCounter struct {
+	nvml.Interface
+	shutdowns atomic.Int32
+}
+
+func (s *shutdownCounter) Shutdown() nvml.Return {
+	s.shutdowns.Add(1)
+	return s.Interface.Shutdown()
+}
+
+func TestSessionShutdownWaitsForDevices(t *testing.T) {
+	// The first device is too slow to finish in time, the second one finds
+	// the driver gone
+	fixture := writeFixture(t, `
+devices:
+  - uuid: GPU-0
+    delay: 5ms
+  - uuid: GPU-1
+    minor: 1
+    returns: {GetTemperature: DRIVER_NOT_LOADED}
+`)
+	fake, err := newFakeNvml(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib := &shutdownCounter{Interface: fake}
+	collectors := map[string]Collector{
+		"temperature": newTemperatureCollector(collectorConfig{DeviceLabels: []string{"minor"}}),
+	}
+	poller := NewPoller(NewSession(lib), collectConfig{DeviceTimeout: time.Millisecond}, collectors, []string{"minor"}, 0, 0)
+	data := poller.Latest(collectors)
+	if reason := downReason(data.Err); reason != "driver_not_loaded" {
+		t.Fatalf("collection failed with %q, want driver_not_loaded", reason)
+	}
+	if n := lib.shutdowns.Load(); n != 0 {
+		t.Errorf("NVML was shut down %d times while a device was still collected", n)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for lib.shutdowns.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := lib.shutdowns.Load(); n != 1 {
+		t.Errorf("NVML was shut down %d times, want 1", n)
+	}
+}